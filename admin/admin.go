@@ -0,0 +1,361 @@
+// Package admin exposes an http.Handler to inspect and change chronolog minimum
+// levels at runtime, without a redeploy.
+//
+// GET returns the current levels and PUT changes them:
+//
+//	PUT /debug/log-levels
+//	{"minimum_level": "info", "loggers": {"payments/*": "debug"}, "duration": "10m"}
+//
+// With a duration, the changes revert automatically once it elapses. Every change,
+// including automatic reverts, is recorded with an entries.LevelChangeLogEntry.
+//
+// The handler performs no authentication: mount it on an internal listener or
+// behind the application's own authorization middleware.
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Astronotify/chronolog"
+	"github.com/Astronotify/chronolog/entries"
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+// maxBodyBytes bounds the size of PUT bodies.
+const maxBodyBytes = 64 << 10
+
+// Audit sources recorded in LevelChangeLogEntry.Source.
+const (
+	SourceAdmin   = "admin"
+	SourceExpired = "expired"
+)
+
+// Options configures a Handler.
+//
+// Fields:
+//
+//   - Logger: the logger whose levels are controlled, shared with its named loggers.
+//     Defaults to chronolog.Default() at the time of each request.
+//   - MaxDuration: the longest accepted time-boxed change. Zero means no limit.
+type Options struct {
+	Logger      *chronolog.Logger
+	MaxDuration time.Duration
+}
+
+// State is the JSON document returned by GET and PUT.
+//
+// Fields:
+//
+//   - MinimumLevel: the global minimum level.
+//   - Loggers: the per-name minimum levels, keyed by normalized prefix.
+//   - Expiring: the time-boxed changes still pending, ordered by logger.
+type State struct {
+	MinimumLevel Level.LogLevel            `json:"minimum_level"`
+	Loggers      map[string]Level.LogLevel `json:"loggers"`
+	Expiring     []Expiring                `json:"expiring,omitempty"`
+}
+
+// Expiring describes a time-boxed change and the level it reverts to.
+// Logger is empty for the global minimum level, and RevertTo is empty when the
+// logger had no override before the change.
+type Expiring struct {
+	Logger    string         `json:"logger,omitempty"`
+	Level     Level.LogLevel `json:"level"`
+	RevertTo  Level.LogLevel `json:"revert_to"`
+	ExpiresAt time.Time      `json:"expires_at"`
+}
+
+// Update is the JSON body accepted by PUT. Omitted fields are left unchanged.
+//
+// Fields:
+//
+//   - MinimumLevel: the new global minimum level.
+//   - Loggers: the per-name levels to set, keyed by pattern (see chronolog.Logger.SetLoggerLevel).
+//     An empty level removes the override.
+//   - Duration: a Go duration such as "10m". When set, the changes revert once it elapses.
+type Update struct {
+	MinimumLevel Level.LogLevel            `json:"minimum_level,omitempty"`
+	Loggers      map[string]Level.LogLevel `json:"loggers,omitempty"`
+	Duration     string                    `json:"duration,omitempty"`
+}
+
+// target identifies one level: the global minimum or a logger prefix.
+type target struct {
+	global bool
+	prefix string
+}
+
+// pendingKey identifies a pending revert. It includes the logger because, without
+// Options.Logger, each request controls whichever logger is the default by then.
+type pendingKey struct {
+	logger *chronolog.Logger
+	target target
+}
+
+// revert is a pending time-boxed change.
+type revert struct {
+	logger    *chronolog.Logger
+	timer     *time.Timer
+	applied   Level.LogLevel
+	previous  Level.LogLevel
+	expiresAt time.Time
+}
+
+// audit is a level change entry waiting to be written once h.mu is released, so
+// that a slow or blocking sink never holds up other requests or expiries.
+type audit struct {
+	ctx    context.Context
+	logger *chronolog.Logger
+	entry  entries.LevelChangeLogEntry
+}
+
+// Handler serves the level control API. Create it with NewHandler.
+type Handler struct {
+	opts Options
+
+	mu      sync.Mutex
+	pending map[pendingKey]*revert
+}
+
+// NewHandler creates a Handler.
+//
+// Parameters:
+//
+//   - opts (Options): the handler configuration.
+//
+// Returns:
+//
+//   - *Handler: an http.Handler serving GET and PUT.
+func NewHandler(opts Options) *Handler {
+	return &Handler{opts: opts, pending: map[pendingKey]*revert{}}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		writeJSON(w, http.StatusOK, h.state(h.logger()))
+	case http.MethodPut:
+		h.update(w, r)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT")
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	}
+}
+
+// Close cancels the pending time-boxed changes without reverting them.
+func (h *Handler) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for key, p := range h.pending {
+		p.timer.Stop()
+		delete(h.pending, key)
+	}
+}
+
+func (h *Handler) logger() *chronolog.Logger {
+	if h.opts.Logger != nil {
+		return h.opts.Logger
+	}
+	return chronolog.Default()
+}
+
+func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
+	var update Update
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid body: %w", err))
+		return
+	}
+
+	duration, err := h.validate(update)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	logger := h.logger()
+	var expiresAt time.Time
+	if duration > 0 {
+		expiresAt = time.Now().Add(duration)
+	}
+	data := map[string]any{"remote_addr": r.RemoteAddr}
+
+	var audits []audit
+	h.mu.Lock()
+	if update.MinimumLevel != "" {
+		audits = h.apply(r.Context(), audits, logger, target{global: true}, update.MinimumLevel, duration, expiresAt, data)
+	}
+	patterns := make([]string, 0, len(update.Loggers))
+	for pattern := range update.Loggers {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		t := target{prefix: chronolog.NormalizeLoggerPattern(pattern)}
+		audits = h.apply(r.Context(), audits, logger, t, update.Loggers[pattern], duration, expiresAt, data)
+	}
+	h.mu.Unlock()
+	write(audits)
+
+	writeJSON(w, http.StatusOK, h.state(logger))
+}
+
+// validate checks every level before anything is changed, so that an update is
+// applied entirely or not at all.
+func (h *Handler) validate(update Update) (time.Duration, error) {
+	if update.MinimumLevel != "" && !Level.IsKnown(update.MinimumLevel) {
+		return 0, fmt.Errorf("minimum_level: unknown level %q", update.MinimumLevel)
+	}
+	for pattern, level := range update.Loggers {
+		if level != "" && !Level.IsKnown(level) {
+			return 0, fmt.Errorf("loggers[%q]: unknown level %q", pattern, level)
+		}
+	}
+	if update.Duration == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(update.Duration)
+	switch {
+	case err != nil:
+		return 0, fmt.Errorf("duration: %w", err)
+	case duration <= 0:
+		return 0, errors.New("duration: must be positive")
+	case h.opts.MaxDuration > 0 && duration > h.opts.MaxDuration:
+		return 0, fmt.Errorf("duration: exceeds the maximum of %s", h.opts.MaxDuration)
+	}
+	return duration, nil
+}
+
+// apply changes one level and schedules its revert, appending the audit entry to
+// audits. h.mu must be held.
+func (h *Handler) apply(ctx context.Context, audits []audit, logger *chronolog.Logger, t target, level Level.LogLevel, duration time.Duration, expiresAt time.Time, data map[string]any) []audit {
+	previous := current(logger, t)
+
+	// a time-boxed change reverts to the level in effect before the first of a
+	// series of overlapping changes; a permanent change cancels the revert
+	revertTo := previous
+	key := pendingKey{logger: logger, target: t}
+	if p, ok := h.pending[key]; ok {
+		p.timer.Stop()
+		delete(h.pending, key)
+		revertTo = p.previous
+	}
+
+	if duration > 0 {
+		p := &revert{logger: logger, applied: level, previous: revertTo, expiresAt: expiresAt}
+		p.timer = time.AfterFunc(duration, func() { h.expire(key, p) })
+		h.pending[key] = p
+	}
+
+	var expires *time.Time
+	if duration > 0 {
+		expires = &expiresAt
+	}
+	return set(ctx, audits, logger, t, previous, level, SourceAdmin, expires, data)
+}
+
+// expire reverts a time-boxed change unless it was superseded in the meantime.
+func (h *Handler) expire(key pendingKey, p *revert) {
+	h.mu.Lock()
+	if h.pending[key] != p {
+		h.mu.Unlock()
+		return
+	}
+	delete(h.pending, key)
+
+	var audits []audit
+	if current(p.logger, key.target) == p.applied { // otherwise changed through another path since
+		audits = set(context.Background(), audits, p.logger, key.target, p.applied, p.previous, SourceExpired, nil, nil)
+	}
+	h.mu.Unlock()
+	write(audits)
+}
+
+func (h *Handler) state(logger *chronolog.Logger) State {
+	state := State{
+		MinimumLevel: logger.MinimumLevel(),
+		Loggers:      logger.LoggerLevels(),
+	}
+
+	h.mu.Lock()
+	for key, p := range h.pending {
+		if key.logger != logger {
+			continue
+		}
+		state.Expiring = append(state.Expiring, Expiring{
+			Logger:    key.target.prefix,
+			Level:     p.applied,
+			RevertTo:  p.previous,
+			ExpiresAt: p.expiresAt,
+		})
+	}
+	h.mu.Unlock()
+
+	sort.Slice(state.Expiring, func(i, j int) bool {
+		return state.Expiring[i].Logger < state.Expiring[j].Logger
+	})
+	return state
+}
+
+func current(logger *chronolog.Logger, t target) Level.LogLevel {
+	if t.global {
+		return logger.MinimumLevel()
+	}
+	return logger.LoggerLevels()[t.prefix]
+}
+
+// set changes a level and appends its audit entry to audits, to be written with
+// write once h.mu is released.
+//
+// The entry is emitted at warn, or at the global minimum level when it is higher,
+// so that the thresholds being changed cannot hide their own audit.
+func set(ctx context.Context, audits []audit, logger *chronolog.Logger, t target, previous, level Level.LogLevel, source string, expiresAt *time.Time, data map[string]any) []audit {
+	if previous == level {
+		return audits
+	}
+
+	if t.global {
+		_ = logger.SetMinimumLevel(level)
+	} else {
+		_ = logger.SetLoggerLevel(t.prefix, level)
+	}
+
+	entry := entries.NewLevelChangeLogEntry(ctx, t.prefix, previous, level, source, data)
+	entry.ExpiresAt = expiresAt
+	if minimum := logger.MinimumLevel(); priority(minimum) > priority(entry.Level) {
+		entry.Level = minimum
+	}
+	return append(audits, audit{ctx: ctx, logger: logger, entry: entry})
+}
+
+// write records the audit entries collected by set. h.mu must not be held.
+func write(audits []audit) {
+	for _, a := range audits {
+		a.logger.Entry(a.ctx, a.entry)
+	}
+}
+
+func priority(level Level.LogLevel) int {
+	p, _ := Level.Priority(level)
+	return p
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
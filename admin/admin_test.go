@@ -0,0 +1,192 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Astronotify/chronolog"
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func do(t *testing.T, h http.Handler, method, body string) (*httptest.ResponseRecorder, State) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, "/log-levels", strings.NewReader(body)))
+	var state State
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec, state
+}
+
+func TestGetAndPut(t *testing.T) {
+	var out syncBuffer
+	logger := chronolog.New(chronolog.Config{Writer: &out, MinimumLogLevel: Level.Info})
+	h := NewHandler(Options{Logger: logger})
+	defer h.Close()
+
+	_, state := do(t, h, http.MethodGet, "")
+	if state.MinimumLevel != Level.Info || len(state.Loggers) != 0 {
+		t.Fatalf("initial state = %+v", state)
+	}
+
+	rec, state := do(t, h, http.MethodPut, `{"minimum_level":"error","loggers":{"payments/*":"debug"}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d: %s", rec.Code, rec.Body)
+	}
+	if state.MinimumLevel != Level.Error || state.Loggers["payments"] != Level.Debug {
+		t.Errorf("state after PUT = %+v", state)
+	}
+
+	audit := out.String()
+	for _, want := range []string{
+		`"event_type":"LevelChangeLogEntry"`,
+		`"previous_level":"info","new_level":"error","source":"admin"`,
+		`"target_logger":"payments","previous_level":"","new_level":"debug"`,
+	} {
+		if !strings.Contains(audit, want) {
+			t.Errorf("audit output lacks %s:\n%s", want, audit)
+		}
+	}
+}
+
+func TestTimeBoxedOverrideReverts(t *testing.T) {
+	var out syncBuffer
+	logger := chronolog.New(chronolog.Config{Writer: &out, MinimumLogLevel: Level.Warn})
+	h := NewHandler(Options{Logger: logger})
+	defer h.Close()
+
+	_, state := do(t, h, http.MethodPut, `{"minimum_level":"debug","duration":"50ms"}`)
+	if state.MinimumLevel != Level.Debug || len(state.Expiring) != 1 || state.Expiring[0].RevertTo != Level.Warn {
+		t.Fatalf("state = %+v", state)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for logger.MinimumLevel() != Level.Warn {
+		if time.Now().After(deadline) {
+			t.Fatal("override did not revert")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !strings.Contains(out.String(), `"source":"expired"`) {
+		t.Errorf("revert was not audited:\n%s", out.String())
+	}
+}
+
+func TestRevertsFollowTheDefaultLogger(t *testing.T) {
+	previous := chronolog.Default()
+	defer chronolog.SetDefault(previous)
+
+	first := chronolog.New(chronolog.Config{Writer: &syncBuffer{}, MinimumLogLevel: Level.Info})
+	second := chronolog.New(chronolog.Config{Writer: &syncBuffer{}, MinimumLogLevel: Level.Warn})
+	h := NewHandler(Options{})
+	defer h.Close()
+
+	chronolog.SetDefault(first)
+	do(t, h, http.MethodPut, `{"minimum_level":"debug","duration":"50ms"}`)
+	chronolog.SetDefault(second)
+	_, state := do(t, h, http.MethodPut, `{"minimum_level":"debug","duration":"50ms"}`)
+	if len(state.Expiring) != 1 || state.Expiring[0].RevertTo != Level.Warn {
+		t.Fatalf("state = %+v", state)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for first.MinimumLevel() != Level.Info || second.MinimumLevel() != Level.Warn {
+		if time.Now().After(deadline) {
+			t.Fatalf("levels = %s, %s, want info, warn", first.MinimumLevel(), second.MinimumLevel())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestInvalidRequests(t *testing.T) {
+	logger := chronolog.New(chronolog.Config{Writer: &syncBuffer{}})
+	h := NewHandler(Options{Logger: logger, MaxDuration: time.Hour})
+
+	for body, want := range map[string]int{
+		`{"minimum_level":"verbose"}`:                 http.StatusBadRequest,
+		`{"loggers":{"db":"loud"}}`:                   http.StatusBadRequest,
+		`{"minimum_level":"debug","duration":"2h"}`:   http.StatusBadRequest,
+		`{"minimum_level":"debug","duration":"-1m"}`:  http.StatusBadRequest,
+		`{"minimum_level":"debug","unknown_field":1}`: http.StatusBadRequest,
+	} {
+		if rec, _ := do(t, h, http.MethodPut, body); rec.Code != want {
+			t.Errorf("%s: status = %d, want %d", body, rec.Code, want)
+		}
+	}
+	if logger.MinimumLevel() != Level.Info {
+		t.Error("a rejected update changed the level")
+	}
+
+	if rec, _ := do(t, h, http.MethodDelete, ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE status = %d", rec.Code)
+	}
+}
+
+// blockingWriter blocks every write until release is closed.
+type blockingWriter struct {
+	entered chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.entered) })
+	<-w.release
+	return len(p), nil
+}
+
+func TestAuditIsWrittenWithoutTheLock(t *testing.T) {
+	w := &blockingWriter{entered: make(chan struct{}), release: make(chan struct{})}
+	logger := chronolog.New(chronolog.Config{Writer: w, MinimumLogLevel: Level.Info})
+	h := NewHandler(Options{Logger: logger})
+	defer h.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		do(t, h, http.MethodPut, `{"minimum_level": "debug"}`)
+	}()
+	<-w.entered
+
+	got := make(chan State, 1)
+	go func() {
+		_, state := do(t, h, http.MethodGet, "")
+		got <- state
+	}()
+	select {
+	case state := <-got:
+		if state.MinimumLevel != Level.Debug {
+			t.Errorf("minimum level = %s, want debug", state.MinimumLevel)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("GET blocked behind the audit write")
+	}
+	close(w.release)
+	<-done
+}
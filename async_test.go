@@ -0,0 +1,133 @@
+package chronolog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+// gatedWriter blocks every Write until the gate is opened.
+type gatedWriter struct {
+	gate chan struct{}
+	mu   sync.Mutex
+	buf  bytes.Buffer
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{gate: make(chan struct{})}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *gatedWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestAsyncFlushDeliversEntries(t *testing.T) {
+	w := newGatedWriter()
+	close(w.gate)
+	l := New(Config{Writer: w, Async: &AsyncConfig{QueueSize: 4}})
+
+	ctx := context.Background()
+	for i := 0; i < 20; i++ {
+		l.Info(ctx, "queued")
+	}
+	if err := l.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if got := strings.Count(w.String(), "\n"); got != 20 {
+		t.Errorf("expected 20 entries after flush, got %d", got)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestAsyncDropNewestCountsDrops(t *testing.T) {
+	w := newGatedWriter()
+	l := New(Config{Writer: w, Async: &AsyncConfig{QueueSize: 2, OverflowPolicy: OverflowDropNewest}})
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		l.Info(ctx, "burst")
+	}
+	close(w.gate)
+	l.Close()
+
+	// the worker holds at most one entry while blocked, the queue two more
+	if dropped := l.DroppedEntries(); dropped < 7 {
+		t.Errorf("expected at least 7 dropped entries, got %d", dropped)
+	}
+}
+
+func TestAsyncDropBelowLevelKeepsErrors(t *testing.T) {
+	w := newGatedWriter()
+	l := New(Config{
+		Writer:          w,
+		MinimumLogLevel: Level.Debug,
+		Async:           &AsyncConfig{QueueSize: 1, OverflowPolicy: OverflowDropBelowLevel},
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		l.Debug(ctx, "noise")
+	}
+	done := make(chan struct{})
+	go func() {
+		l.Warn(ctx, "important")
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	close(w.gate)
+	<-done
+	l.Close()
+
+	if !strings.Contains(w.String(), "important") {
+		t.Errorf("expected warn entry to be delivered, got %q", w.String())
+	}
+	if l.DroppedEntries() == 0 {
+		t.Errorf("expected debug entries to be dropped")
+	}
+}
+
+func TestAsyncFlushHonorsContext(t *testing.T) {
+	w := newGatedWriter()
+	l := New(Config{Writer: w, Async: &AsyncConfig{QueueSize: 4}})
+	defer func() {
+		close(w.gate)
+		l.Close()
+	}()
+
+	l.Info(context.Background(), "stuck")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Flush(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestAsyncEntriesAfterCloseAreDropped(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Config{Writer: &buf, Async: &AsyncConfig{}})
+	l.Close()
+
+	l.Info(context.Background(), "late")
+	if l.DroppedEntries() != 1 {
+		t.Errorf("expected late entry to be dropped, got %d", l.DroppedEntries())
+	}
+}
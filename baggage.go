@@ -0,0 +1,44 @@
+package chronolog
+
+import (
+	"context"
+
+	"github.com/Astronotify/chronolog/entries"
+	"github.com/Astronotify/chronolog/internal"
+)
+
+// baggageFilter copies the allowlisted baggage members of the logging context
+// into entries.
+type baggageFilter struct {
+	keys map[string]struct{}
+}
+
+func newBaggageFilter(keys []string) *baggageFilter {
+	if len(keys) == 0 {
+		return nil
+	}
+	f := &baggageFilter{keys: make(map[string]struct{}, len(keys))}
+	for _, key := range keys {
+		f.keys[key] = struct{}{}
+	}
+	return f
+}
+
+// apply returns a copy of entry whose Baggage holds the allowlisted members of
+// ctx, or entry itself when there are none.
+func (f *baggageFilter) apply(ctx context.Context, entry any) any {
+	var baggage map[string]string
+	for _, member := range internal.ExtractBaggage(ctx) {
+		if _, ok := f.keys[member.Key]; !ok {
+			continue
+		}
+		if baggage == nil {
+			baggage = make(map[string]string)
+		}
+		baggage[member.Key] = member.Value
+	}
+	if baggage == nil {
+		return entry
+	}
+	return updateLogEntry(entry, func(e *entries.LogEntry) { e.Baggage = baggage })
+}
@@ -4,30 +4,45 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"sync/atomic"
 
-	"github.com/Astronotify/chronolog/entries"
 	"github.com/Astronotify/chronolog/internal"
 	Level "github.com/Astronotify/chronolog/level"
 )
 
-var logger *slog.Logger
-var minimumLogLevel Level.LogLevel = Level.Info
+var defaultLogger atomic.Pointer[Logger]
 
+func init() {
+	defaultLogger.Store(newFallbackLogger())
+}
+
+// Setup configures the package-level default logger used by Trace, Debug, Info,
+// Warn, Error and Entry.
+//
+// The new logger is swapped in atomically, so Setup may safely run concurrently
+// with logging calls. The signal listener of the replaced default logger, if
+// any, is stopped so that only the new one reacts to Config.HandleSignals. Use
+// New instead when a component needs its own isolated configuration.
+//
+// Parameters:
+//   - cfg (Config): the configuration of the default logger.
 func Setup(cfg Config) {
-	cfg.applyDefaults()
-	minimumLogLevel = cfg.MinimumLogLevel
-
-	var handler slog.Handler
-	switch cfg.Format {
-	case FormatPretty:
-		handler = internal.NewPrettyConsoleHandler(cfg.Writer)
-	case FormatJSON:
-		handler = internal.NewJSONOnlyHandler(cfg.Writer)
-	default:
-		handler = internal.NewJSONOnlyHandler(cfg.Writer)
-	}
+	previous := defaultLogger.Swap(New(cfg))
+	previous.signals.stop()
+}
+
+// Default returns the logger used by the package-level logging functions.
+func Default() *Logger {
+	return defaultLogger.Load()
+}
 
-	logger = slog.New(handler)
+// SetDefault atomically replaces the logger used by the package-level logging functions.
+// A nil logger is ignored.
+func SetDefault(l *Logger) {
+	if l == nil {
+		return
+	}
+	defaultLogger.Store(l)
 }
 
 // Trace logs a detailed message for low-level debugging purposes.
@@ -45,14 +60,7 @@ func Setup(cfg Config) {
 // Returns:
 //   - None. This function produces side effects by emitting a log entry through the logger pipeline.
 func Trace(ctx context.Context, message string, additionalData ...map[string]any) {
-	entry := entries.NewLogEntry(
-		ctx,
-		Level.Trace,
-		message,
-		internal.MergeAdditionalData(additionalData...),
-	)
-
-	write(ctx, entry)
+	Default().Trace(ctx, message, additionalData...)
 }
 
 // Debug logs a message for detailed debugging information.
@@ -69,14 +77,7 @@ func Trace(ctx context.Context, message string, additionalData ...map[string]any
 // Returns:
 //   - None. This function produces side effects by emitting a log entry through the logger pipeline.
 func Debug(ctx context.Context, message string, additionalData ...map[string]any) {
-	entry := entries.NewLogEntry(
-		ctx,
-		Level.Debug,
-		message,
-		internal.MergeAdditionalData(additionalData...),
-	)
-
-	write(ctx, entry)
+	Default().Debug(ctx, message, additionalData...)
 }
 
 // Info logs an informational message, optionally enriched with additional contextual data.
@@ -94,14 +95,7 @@ func Debug(ctx context.Context, message string, additionalData ...map[string]any
 // Returns:
 //   - None. This function produces side effects by emitting a log entry through the logger pipeline.
 func Info(ctx context.Context, message string, additionalData ...map[string]any) {
-	entry := entries.NewLogEntry(
-		ctx,
-		Level.Info,
-		message,
-		internal.MergeAdditionalData(additionalData...),
-	)
-
-	write(ctx, entry)
+	Default().Info(ctx, message, additionalData...)
 }
 
 // Warn logs a warning message indicating a potential issue that is not necessarily an error.
@@ -117,14 +111,55 @@ func Info(ctx context.Context, message string, additionalData ...map[string]any)
 // Returns:
 //   - None. The log entry is processed and forwarded to the underlying logging system.
 func Warn(ctx context.Context, message string, additionalData ...map[string]any) {
-	entry := entries.NewLogEntry(
-		ctx,
-		Level.Warn,
-		message,
-		internal.MergeAdditionalData(additionalData...),
-	)
+	Default().Warn(ctx, message, additionalData...)
+}
+
+// Named returns a logger whose entries carry the given name and honor per-name
+// minimum levels. See Logger.Named.
+//
+// The returned logger resolves the default logger on every call rather than when
+// Named is called, so package-level loggers such as
+//
+//	var log = chronolog.Named("payments")
+//
+// write through the sinks and levels configured later by Setup.
+//
+// Parameters:
+//   - name (string): the logger name, such as "payments.ledger".
+//
+// Returns:
+//   - *Logger: the named logger.
+func Named(name string) *Logger {
+	return (&Logger{followsDefault: true}).Named(name)
+}
+
+// SetMinimumLevel changes the global minimum level of the default logger at runtime.
+// See Logger.SetMinimumLevel.
+func SetMinimumLevel(level Level.LogLevel) error {
+	return Default().SetMinimumLevel(level)
+}
 
-	write(ctx, entry)
+// SetLoggerLevel changes the minimum level of the default logger's named loggers
+// matching pattern at runtime. See Logger.SetLoggerLevel.
+func SetLoggerLevel(pattern string, level Level.LogLevel) error {
+	return Default().SetLoggerLevel(pattern, level)
+}
+
+// Log logs a message at the given level, typically a custom level registered with
+// Level.Register such as "notice" or "audit".
+//
+// Entries whose level is neither built-in nor registered are discarded.
+//
+// Parameters:
+//   - ctx (context.Context): The execution context for the log entry.
+//   - level (Level.LogLevel): The level of the entry.
+//   - message (string): The log message.
+//   - additionalData (...map[string]any): Optional structured data to attach to the log.
+//
+// Returns:
+//   - None. The log entry is processed and forwarded to the underlying logging system.
+func Log(ctx context.Context, level Level.LogLevel, message string, additionalData ...map[string]any) {
+	Default().Log(ctx, level, message, additionalData...)
 }
 
 // Error logs a structured error message, along with optional diagnostic data.
@@ -140,13 +175,41 @@ func Warn(ctx context.Context, message string, additionalData ...map[string]any)
 // Returns:
 //   - None. The error is emitted as a structured log entry.
 func Error(ctx context.Context, err error, additionalData ...map[string]any) {
-	entry := entries.NewErrorLogEntry(
-		ctx,
-		err,
-		internal.MergeAdditionalData(additionalData...),
-	)
+	Default().Error(ctx, err, additionalData...)
+}
+
+// Panic logs err at Level.Panic, flushes the default logger and panics with err.
+//
+// Use it instead of pairing Error with a panic call, which may lose entries still
+// buffered by asynchronous sinks. The flush is bounded by a short timeout so that a
+// stuck writer cannot prevent the panic.
+//
+// Parameters:
+//   - ctx (context.Context): The context for propagating metadata like correlation IDs.
+//   - err (error): The error instance to log and panic with. Expected to be non-nil.
+//   - additionalData (...map[string]any): Optional structured metadata to assist in debugging.
+//
+// Returns:
+//   - None. Panic never returns.
+func Panic(ctx context.Context, err error, additionalData ...map[string]any) {
+	Default().Panic(ctx, err, additionalData...)
+}
 
-	write(ctx, entry)
+// Fatal logs err at Level.Fatal, flushes the default logger and terminates the process
+// with status 1 through the configured Config.ExitFunc (os.Exit by default).
+//
+// Use it instead of pairing Error with os.Exit, which loses entries still buffered by
+// asynchronous sinks. Deferred functions are not run.
+//
+// Parameters:
+//   - ctx (context.Context): The context for propagating metadata like correlation IDs.
+//   - err (error): The error instance to log. Expected to be non-nil.
+//   - additionalData (...map[string]any): Optional structured metadata to assist in debugging.
+//
+// Returns:
+//   - None. Fatal does not return unless ExitFunc does.
+func Fatal(ctx context.Context, err error, additionalData ...map[string]any) {
+	Default().Fatal(ctx, err, additionalData...)
 }
 
 // Entry logs a fully preconstructed log entry.
@@ -162,44 +225,61 @@ func Error(ctx context.Context, err error, additionalData ...map[string]any) {
 // Returns:
 //   - None. The entry is passed directly to the logger for serialization and dispatch.
 func Entry(ctx context.Context, entry any) {
-	write(ctx, entry)
+	Default().Entry(ctx, entry)
 }
 
-// write is a low-level utility that emits the final log event to the logger backend.
-//
-// This internal function abstracts the actual call to the logging system (e.g., slog, zap, zerolog).
-// It is not meant to be used directly. Instead, prefer higher-level helpers like Info, Warn, or Error.
-//
-// Parameters:
-//   - ctx (context.Context): The context for metadata propagation.
-//   - entry (any): The structured log event to be emitted.
-//
-// Returns:
-//   - None. Side-effect: sends the log entry to the logger.
-func write(ctx context.Context, entry any) {
-	level := extractLogLevel(entry)
-	if !shouldLog(level) {
-		return
-	}
-	if logger == nil {
-		logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+// Flush waits until the default logger has written every entry logged before the call.
+// See Logger.Flush.
+func Flush(ctx context.Context) error {
+	return Default().Flush(ctx)
+}
+
+// Close drains and shuts down the default logger. See Logger.Close.
+func Close() error {
+	return Default().Close()
+}
+
+func mapOverflowPolicy(policy OverflowPolicy) internal.OverflowPolicy {
+	switch policy {
+	case OverflowDropNewest:
+		return internal.OverflowDropNewest
+	case OverflowDropOldest:
+		return internal.OverflowDropOldest
+	case OverflowDropBelowLevel:
+		return internal.OverflowDropBelowLevel
+	default:
+		return internal.OverflowBlock
 	}
-	logger.Log(ctx, mapLogLevel(level), "log", slog.Any("event", entry))
 }
 
+// mapLogLevel returns the slog level of a built-in or registered level.
+// Unknown levels map to slog.LevelInfo.
 func mapLogLevel(level Level.LogLevel) slog.Level {
-	switch level {
-	case Level.Trace, Level.Debug:
-		return slog.LevelDebug
-	case Level.Info:
-		return slog.LevelInfo
-	case Level.Warn:
-		return slog.LevelWarn
-	case Level.Error:
-		return slog.LevelError
-	default:
-		return slog.LevelInfo
+	if slogLevel, ok := Level.SlogLevel(level); ok {
+		return slogLevel
+	}
+	return slog.LevelInfo
+}
+
+// levelEnabled reports whether an entry of the given level passes the minimum level.
+//
+// Entries with a level that is neither built-in nor registered are rejected rather
+// than guessed, so a typo cannot leak debug output into production sinks.
+func levelEnabled(level, minimum Level.LogLevel) bool {
+	priority, ok := Level.Priority(level)
+	if !ok {
+		return false
+	}
+	threshold, _ := Level.Priority(minimum)
+	return priority >= threshold
+}
+
+// knownLevelOr returns level if it is built-in or registered, fallback otherwise.
+func knownLevelOr(level, fallback Level.LogLevel) Level.LogLevel {
+	if Level.IsKnown(level) {
+		return level
 	}
+	return fallback
 }
 
 func (c *Config) applyDefaults() {
@@ -209,19 +289,79 @@ func (c *Config) applyDefaults() {
 	if c.Format == "" {
 		c.Format = FormatJSON
 	}
+	if c.GCPProjectID == "" {
+		c.GCPProjectID = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+	if len(c.Sinks) > 0 {
+		c.applySinkDefaults()
+	}
+	c.MinimumLogLevel = knownLevelOr(c.MinimumLogLevel, Level.Info)
+	c.Async = c.Async.withDefaults()
+	if c.ExitFunc == nil {
+		c.ExitFunc = os.Exit
+	}
+}
+
+// applySinkDefaults fills each sink and, when no global level was configured,
+// lowers the global threshold to the most verbose sink so it can receive its entries.
+func (c *Config) applySinkDefaults() {
+	sinks := make([]SinkConfig, len(c.Sinks))
+	for i, sink := range c.Sinks {
+		if sink.Writer == nil {
+			sink.Writer = os.Stdout
+		}
+		if sink.Format == "" {
+			sink.Format = FormatJSON
+		}
+		if sink.MinimumLogLevel == "" {
+			sink.MinimumLogLevel = c.MinimumLogLevel
+		}
+		sink.MinimumLogLevel = knownLevelOr(sink.MinimumLogLevel, Level.Info)
+		sink.Async = sink.Async.withDefaults()
+		sinks[i] = sink
+	}
+	c.Sinks = sinks
+
 	if c.MinimumLogLevel == "" {
-		c.MinimumLogLevel = Level.Info
+		lowest := sinks[0].MinimumLogLevel
+		for _, sink := range sinks[1:] {
+			if !levelEnabled(sink.MinimumLogLevel, lowest) {
+				lowest = sink.MinimumLogLevel
+			}
+		}
+		c.MinimumLogLevel = lowest
 	}
 }
 
+// withDefaults returns a copy of the configuration with zero values replaced, or nil.
+func (a *AsyncConfig) withDefaults() *AsyncConfig {
+	if a == nil {
+		return nil
+	}
+	async := *a
+	if async.QueueSize <= 0 {
+		async.QueueSize = 1024
+	}
+	if async.OverflowPolicy == "" {
+		async.OverflowPolicy = OverflowBlock
+	}
+	if async.DropBelowLevel == "" {
+		async.DropBelowLevel = Level.Warn
+	}
+	return &async
+}
+
+func extractLoggerName(entry any) string {
+	if e, ok := entry.(interface{ GetLoggerName() string }); ok {
+		return e.GetLoggerName()
+	}
+	return ""
+}
+
 func extractLogLevel(entry any) Level.LogLevel {
-	if e, ok := entry.(interface{ GetLevel() Level.LogLevel }); ok {
+	if e, ok := entry.(interface{ GetLevel() Level.LogLevel }); ok && e.GetLevel() != "" {
 		return e.GetLevel()
 	}
 	// fallback: assume info
 	return Level.Info
 }
-
-func shouldLog(level Level.LogLevel) bool {
-	return Level.LogLevelPriority[level] >= Level.LogLevelPriority[minimumLogLevel]
-}
@@ -1,12 +1,20 @@
 package chronolog
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/Astronotify/chronolog/entries"
 	Level "github.com/Astronotify/chronolog/level"
 )
 
@@ -24,15 +32,14 @@ func (h *capturingHandler) WithGroup(string) slog.Handler        { return h }
 
 func TestWriteLevelMapping(t *testing.T) {
 	handler := &capturingHandler{}
-	logger = slog.New(handler)
-	minimumLogLevel = Level.Trace
+	l := newWithHandler(handler, Level.Trace)
 
 	ctx := context.Background()
-	Trace(ctx, "trace")
-	Debug(ctx, "debug")
-	Info(ctx, "info")
-	Warn(ctx, "warn")
-	Error(ctx, errors.New("err"))
+	l.Trace(ctx, "trace")
+	l.Debug(ctx, "debug")
+	l.Info(ctx, "info")
+	l.Warn(ctx, "warn")
+	l.Error(ctx, errors.New("err"))
 
 	want := []slog.Level{
 		slog.LevelDebug,
@@ -47,9 +54,64 @@ func TestWriteLevelMapping(t *testing.T) {
 	}
 }
 
+func TestPackageFunctionsDelegateToDefault(t *testing.T) {
+	previous := Default()
+	t.Cleanup(func() { SetDefault(previous) })
+
+	handler := &capturingHandler{}
+	SetDefault(newWithHandler(handler, Level.Warn))
+
+	ctx := context.Background()
+	Info(ctx, "filtered")
+	Warn(ctx, "warn")
+	Error(ctx, errors.New("err"))
+
+	want := []slog.Level{slog.LevelWarn, slog.LevelError}
+	if !reflect.DeepEqual(handler.levels, want) {
+		t.Errorf("levels mismatch: got %v want %v", handler.levels, want)
+	}
+}
+
+func TestLoggersAreIndependent(t *testing.T) {
+	var a, b bytes.Buffer
+	la := New(Config{Writer: &a, MinimumLogLevel: Level.Debug})
+	lb := New(Config{Writer: &b, MinimumLogLevel: Level.Error})
+
+	ctx := context.Background()
+	la.Debug(ctx, "only a")
+	lb.Debug(ctx, "only a")
+
+	if a.Len() == 0 {
+		t.Errorf("expected debug entry in first logger output")
+	}
+	if b.Len() != 0 {
+		t.Errorf("expected no output from second logger, got %q", b.String())
+	}
+}
+
+func TestConcurrentSetupAndWrite(t *testing.T) {
+	previous := Default()
+	t.Cleanup(func() { SetDefault(previous) })
+
+	SetDefault(New(Config{Writer: io.Discard}))
+
+	var wg sync.WaitGroup
+	ctx := context.Background()
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			Setup(Config{Writer: io.Discard})
+		}()
+		go func() {
+			defer wg.Done()
+			Info(ctx, "concurrent")
+		}()
+	}
+	wg.Wait()
+}
+
 func TestLoggingWithoutSetupDoesNotPanic(t *testing.T) {
-	logger = nil
-	minimumLogLevel = Level.Info
 	ctx := context.Background()
 
 	defer func() {
@@ -58,9 +120,208 @@ func TestLoggingWithoutSetupDoesNotPanic(t *testing.T) {
 		}
 	}()
 
-	Info(ctx, "hello")
+	newFallbackLogger().Info(ctx, "hello")
+
+	if Default() == nil {
+		t.Errorf("default logger should always be initialized")
+	}
+}
+
+func TestFatalFlushesBeforeExit(t *testing.T) {
+	gate := newGatedWriter()
+	// the writer is still blocked when Fatal is called
+	time.AfterFunc(20*time.Millisecond, func() { close(gate.gate) })
+
+	exitCode := -1
+	l := New(Config{
+		Writer:   gate,
+		Format:   FormatPretty,
+		Async:    &AsyncConfig{QueueSize: 8},
+		ExitFunc: func(code int) { exitCode = code },
+	})
+	defer l.Close()
+
+	l.Fatal(context.Background(), errors.New("cannot bind port"))
+
+	if exitCode != 1 {
+		t.Fatalf("exit code = %d, want 1", exitCode)
+	}
+	out := gate.String()
+	if !strings.Contains(out, "FATAL") || !strings.Contains(out, "cannot bind port") {
+		t.Errorf("fatal entry not written before exit: %q", out)
+	}
+}
+
+// stuckFlushWriter is a writer whose Flush never returns until released.
+type stuckFlushWriter struct {
+	bytes.Buffer
+	release chan struct{}
+}
+
+func (w *stuckFlushWriter) Flush() error {
+	<-w.release
+	return nil
+}
+
+func TestFlushDeadlineCoversWriters(t *testing.T) {
+	w := &stuckFlushWriter{release: make(chan struct{})}
+	defer close(w.release)
+	l := New(Config{Writer: w})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := l.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Flush() = %v, want the deadline error", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Flush() returned after %v", elapsed)
+	}
+}
+
+func TestPanicFlushesAndPanics(t *testing.T) {
+	handler := &capturingHandler{}
+	l := newWithHandler(handler, Level.Info)
+	boom := errors.New("invariant violated")
+
+	defer func() {
+		if r := recover(); r != boom {
+			t.Errorf("recovered %v, want the logged error", r)
+		}
+		if want := []slog.Level{slog.LevelError + 4}; !reflect.DeepEqual(handler.levels, want) {
+			t.Errorf("levels = %v, want %v", handler.levels, want)
+		}
+	}()
+	l.Panic(context.Background(), boom)
+}
+
+// customLevelRuns numbers the runs of TestCustomLevels.
+var customLevelRuns atomic.Int32
+
+func TestCustomLevels(t *testing.T) {
+	// the registry is process-wide, so every run (e.g. go test -count=2) needs its own name
+	notice := Level.LogLevel(fmt.Sprintf("notice-%d", customLevelRuns.Add(1)))
+	if err := Level.Register(notice, 25, slog.LevelInfo+2); err != nil {
+		t.Fatal(err)
+	}
+	if err := Level.Register(Level.Info, 1, slog.LevelInfo); !errors.Is(err, Level.ErrInvalidLevel) {
+		t.Errorf("re-registering a built-in level: err = %v", err)
+	}
+
+	ctx := context.Background()
+	render := func(format Format, minimum Level.LogLevel, level Level.LogLevel) string {
+		var buf bytes.Buffer
+		New(Config{Writer: &buf, Format: format, MinimumLogLevel: minimum}).Log(ctx, level, "disk almost full")
+		return buf.String()
+	}
+
+	upper := strings.ToUpper(string(notice))
+	for format, want := range map[Format]string{
+		FormatJSON:     `"level":"` + string(notice) + `"`,
+		FormatPretty:   upper,
+		FormatLogfmt:   "level=" + string(notice),
+		FormatGCP:      `"severity":"NOTICE"`,
+		FormatOTLPJSON: `"severityNumber":11,"severityText":"` + upper + `"`,
+		FormatECS:      `"log.level":"` + string(notice) + `"`,
+	} {
+		if got := render(format, Level.Info, notice); !strings.Contains(got, want) {
+			t.Errorf("%s: %q does not contain %q", format, got, want)
+		}
+	}
+
+	if got := render(FormatJSON, Level.Warn, notice); got != "" {
+		t.Errorf("notice passed a warn threshold: %q", got)
+	}
+	if got := render(FormatJSON, notice, Level.Info); got != "" {
+		t.Errorf("info passed a notice threshold: %q", got)
+	}
+	if got := render(FormatJSON, Level.Trace, "verbose"); got != "" {
+		t.Errorf("unknown level was logged: %q", got)
+	}
+}
 
-	if logger == nil {
-		t.Errorf("logger should be initialized by write")
+func TestNamedLoggerLevels(t *testing.T) {
+	var buf bytes.Buffer
+	root := New(Config{
+		Writer:          &buf,
+		MinimumLogLevel: Level.Warn,
+		LoggerLevels:    map[string]Level.LogLevel{"payments/*": Level.Debug},
+	})
+	ledger := root.Named("payments").Named("ledger")
+	shipping := root.Named("shipping")
+	ctx := context.Background()
+
+	ledger.Debug(ctx, "ledger debug")
+	shipping.Info(ctx, "shipping info")
+	root.Named("paymentsx").Debug(ctx, "sibling prefix")
+	if out := buf.String(); !strings.Contains(out, `"logger":"payments.ledger"`) || strings.Contains(out, "shipping info") || strings.Contains(out, "sibling prefix") {
+		t.Fatalf("unexpected output: %s", out)
+	}
+
+	// the longest prefix wins and changes are visible to existing loggers
+	buf.Reset()
+	if err := root.SetLoggerLevel("payments.ledger", Level.Error); err != nil {
+		t.Fatal(err)
+	}
+	if err := shipping.SetMinimumLevel(Level.Info); err != nil {
+		t.Fatal(err)
+	}
+	ledger.Warn(ctx, "ledger warn")
+	root.Named("payments").Named("refunds").Debug(ctx, "refunds debug")
+	shipping.Info(ctx, "shipping info")
+	out := buf.String()
+	if strings.Contains(out, "ledger warn") || !strings.Contains(out, "refunds debug") || !strings.Contains(out, "shipping info") {
+		t.Errorf("unexpected output after update: %s", out)
+	}
+
+	if err := root.SetLoggerLevel("payments", "verbose"); !errors.Is(err, Level.ErrInvalidLevel) {
+		t.Errorf("unknown level accepted: %v", err)
+	}
+	if got := root.LoggerLevels(); !reflect.DeepEqual(got, map[string]Level.LogLevel{"payments": Level.Debug, "payments.ledger": Level.Error}) {
+		t.Errorf("LoggerLevels() = %v", got)
+	}
+}
+
+func TestPackageNamedFollowsDefault(t *testing.T) {
+	previous := Default()
+	t.Cleanup(func() { SetDefault(previous) })
+
+	// declared before Setup, as a package-level variable would be
+	ledger := Named("payments").Named("ledger")
+
+	var buf bytes.Buffer
+	Setup(Config{
+		Writer:          &buf,
+		MinimumLogLevel: Level.Warn,
+		LoggerLevels:    map[string]Level.LogLevel{"payments": Level.Debug},
+	})
+	ledger.Debug(context.Background(), "posting entry")
+	if out := buf.String(); !strings.Contains(out, `"logger":"payments.ledger"`) || !strings.Contains(out, "posting entry") {
+		t.Fatalf("entry did not reach the configured default: %q", out)
+	}
+
+	if err := SetLoggerLevel("payments", Level.Error); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	ledger.Warn(context.Background(), "hidden by the runtime change")
+	if buf.Len() != 0 || ledger.MinimumLevel() != Level.Warn {
+		t.Errorf("named logger ignored the default's levels: %q", buf.String())
+	}
+}
+
+func TestNamedLoggerPrebuiltEntry(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Config{Writer: &buf}).Named("orders")
+
+	entry := entries.NewLogEntry(context.Background(), Level.Info, "prebuilt")
+	l.Entry(context.Background(), &entry)
+
+	if !strings.Contains(buf.String(), `"logger":"orders"`) {
+		t.Errorf("logger name missing: %s", buf.String())
+	}
+	if entry.LoggerName != "" {
+		t.Error("the caller's entry was mutated")
 	}
 }
@@ -0,0 +1,24 @@
+package chronolog
+
+import "github.com/Astronotify/chronolog/internal"
+
+// ErrorClassifier inspects an error and returns the class reported in
+// ErrorLogEntry.ErrorClass, or false when it does not recognize the error.
+type ErrorClassifier func(err error) (class string, ok bool)
+
+// RegisterErrorClassifier adds an application-specific error classifier.
+//
+// Classifiers are consulted in registration order, after errors implementing
+// ChronologClass() string and before the built-in rules for well-known
+// sentinels (context.Canceled, io.EOF, os.ErrNotExist, net.Error timeouts, ...).
+// It is safe to call concurrently with logging, typically from an init function.
+//
+// Parameters:
+//
+//   - classifier (ErrorClassifier): the classifier to add. Nil is ignored.
+func RegisterErrorClassifier(classifier ErrorClassifier) {
+	if classifier == nil {
+		return
+	}
+	internal.RegisterErrorClassifier(internal.ErrorClassifier(classifier))
+}
@@ -11,10 +11,150 @@ type Format string
 const (
 	FormatJSON   Format = "json"
 	FormatPretty Format = "pretty"
+
+	// FormatLogfmt emits one key=value line per entry, with nested data flattened
+	// into dotted keys (e.g. additional_data.user.id=42).
+	FormatLogfmt Format = "logfmt"
+
+	// FormatOTLPJSON emits one OTLP/JSON ExportLogsServiceRequest per line, following
+	// the OpenTelemetry log data model.
+	FormatOTLPJSON Format = "otlp_json"
+
+	// FormatECS emits one Elastic Common Schema document per line, mapping each entry
+	// type onto ECS fields such as log.level, trace.id, error.* and http.*.
+	FormatECS Format = "ecs"
+
+	// FormatGCP emits Google Cloud Logging structured JSON (severity,
+	// logging.googleapis.com/trace, httpRequest, ...) for GKE and Cloud Run.
+	FormatGCP Format = "gcp"
+
+	// FormatEMF emits JSON enriched with the AWS CloudWatch Embedded Metric Format,
+	// turning the DurationMs of configured entry types into CloudWatch metrics.
+	FormatEMF Format = "emf"
 )
 
+// OverflowPolicy selects what an asynchronous logger does when its queue is full.
+type OverflowPolicy string
+
+const (
+	// OverflowBlock makes the logging call wait until the queue has room. This is the default.
+	OverflowBlock OverflowPolicy = "block"
+
+	// OverflowDropNewest discards the entry being logged.
+	OverflowDropNewest OverflowPolicy = "drop_newest"
+
+	// OverflowDropOldest evicts the oldest queued entry to make room for the new one.
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+
+	// OverflowDropBelowLevel discards entries below AsyncConfig.DropBelowLevel and
+	// blocks for the others.
+	OverflowDropBelowLevel OverflowPolicy = "drop_below_level"
+)
+
+// AsyncConfig enables asynchronous delivery: entries are enqueued into a bounded
+// queue and encoded and written by a background goroutine.
+//
+// Fields:
+//
+//   - QueueSize: capacity of the queue. Defaults to 1024.
+//   - OverflowPolicy: behavior when the queue is full. Defaults to OverflowBlock.
+//   - DropBelowLevel: threshold used by OverflowDropBelowLevel. Defaults to Level.Warn.
+type AsyncConfig struct {
+	QueueSize      int
+	OverflowPolicy OverflowPolicy
+	DropBelowLevel Level.LogLevel
+}
+
+// EMFDimension declares a CloudWatch dimension named Name whose value is read
+// from the entry field with JSON name Field (e.g. {Name: "FunctionName", Field: "function_name"}).
+type EMFDimension struct {
+	Name  string
+	Field string
+}
+
+// EMFMetric declares the DurationMs metric published for one entry type.
+type EMFMetric struct {
+	Dimensions []EMFDimension
+}
+
+// EMFConfig configures FormatEMF.
+//
+// Fields:
+//
+//   - Namespace: CloudWatch namespace of the metrics. Defaults to "Chronolog".
+//   - Metrics: metric declaration per event type (e.g. "LambdaEndLogEntry"). Defaults to
+//     DefaultEMFMetrics. Entry types without a declaration are written as plain JSON.
+type EMFConfig struct {
+	Namespace string
+	Metrics   map[string]EMFMetric
+}
+
+// DefaultEMFMetrics returns the metric declarations used when EMFConfig.Metrics is nil:
+// DurationMs of Lambda, operation, message and trace completions, with the
+// dimensions identifying what was measured.
+func DefaultEMFMetrics() map[string]EMFMetric {
+	return map[string]EMFMetric{
+		"LambdaEndLogEntry": {Dimensions: []EMFDimension{
+			{Name: "FunctionName", Field: "function_name"},
+		}},
+		"OperationResponseLogEntry": {Dimensions: []EMFDimension{
+			{Name: "OperationName", Field: "operation_name"},
+			{Name: "HTTPStatus", Field: "http_status"},
+		}},
+		"MessageAcknowledgedLogEntry": {Dimensions: []EMFDimension{
+			{Name: "Topic", Field: "topic"},
+		}},
+		"MessageRejectedLogEntry": {Dimensions: []EMFDimension{
+			{Name: "Topic", Field: "topic"},
+		}},
+		"TraceEndLogEntry": {Dimensions: []EMFDimension{
+			{Name: "TraceName", Field: "name"},
+		}},
+	}
+}
+
 type Config struct {
 	Writer          io.Writer
 	Format          Format
 	MinimumLogLevel Level.LogLevel
+
+	// Async enables asynchronous delivery when non-nil. Call Flush or Close before
+	// the process exits to make sure queued entries are written.
+	Async *AsyncConfig
+
+	// Sinks fans entries out to several destinations, each with its own writer,
+	// format and level. When set, Writer and Format are ignored.
+	Sinks []SinkConfig
+
+	// GCPProjectID is used by FormatGCP to qualify trace IDs as
+	// projects/<id>/traces/<trace>. Defaults to the GOOGLE_CLOUD_PROJECT
+	// environment variable.
+	GCPProjectID string
+
+	// EMF configures FormatEMF. When nil, the "Chronolog" namespace and
+	// DefaultEMFMetrics are used.
+	EMF *EMFConfig
+
+	// LoggerLevels sets the minimum level of named loggers by name prefix, e.g.
+	// {"payments/*": Level.Debug}. The longest matching prefix wins; loggers matching
+	// none use MinimumLogLevel. Entries must still pass the level of their sink.
+	LoggerLevels map[string]Level.LogLevel
+
+	// BaggageKeys lists the W3C baggage keys (see ctx.FromBaggage) copied into the
+	// Baggage field of every entry logged with a context carrying them. Baggage
+	// crosses trust boundaries, so nothing is copied unless allowlisted here.
+	BaggageKeys []string
+
+	// HandleSignals lets operators change verbosity without an HTTP port (Unix only):
+	// SIGUSR1 steps the minimum level down to the next more verbose level, SIGUSR2
+	// steps it up but never past error, and SIGHUP reopens writers exposing a
+	// Reopen() error method, such as the file sink, whose own ReopenOnSIGHUP listener
+	// is then stopped. Each level change is announced
+	// with a LevelChangeLogEntry. The signals are unregistered by Close, and Setup
+	// stops those of the default logger it replaces.
+	HandleSignals bool
+
+	// ExitFunc terminates the process after Fatal has flushed the sinks.
+	// Defaults to os.Exit; tests can replace it to observe the exit code.
+	ExitFunc func(code int)
 }
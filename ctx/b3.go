@@ -0,0 +1,168 @@
+package ctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Astronotify/chronolog/internal"
+)
+
+// Zipkin B3 header names.
+const (
+	B3Header             = "b3"
+	B3TraceIDHeader      = "X-B3-TraceId"
+	B3SpanIDHeader       = "X-B3-SpanId"
+	B3ParentSpanIDHeader = "X-B3-ParentSpanId"
+	B3SampledHeader      = "X-B3-Sampled"
+	B3FlagsHeader        = "X-B3-Flags"
+)
+
+// ErrInvalidB3 is returned for B3 headers that do not follow the Zipkin B3 format.
+var ErrInvalidB3 = errors.New("invalid b3")
+
+// FromB3 stores the trace ID, span ID, parent span ID and sampling decision of
+// Zipkin B3 headers, read from the single "b3" header when present and from the
+// X-B3-* headers otherwise.
+//
+// 64-bit trace IDs are left-padded with zeros to 128 bits, so that entries of B3
+// services correlate with W3C Trace Context ones. The debug flag ("d" or
+// X-B3-Flags: 1) is recorded as sampled. When a trace ID is accepted, the parent
+// span ID and the sampling decision of ctx are always replaced, and cleared when
+// the headers carry none.
+//
+// Parameters:
+//   - ctx (context.Context): the parent context.
+//   - header (http.Header): the incoming request headers.
+//
+// Returns:
+//   - context.Context: the context carrying the trace, ctx unchanged when no B3
+//     header is present or on error.
+//   - error: an error wrapping ErrInvalidB3 if an identifier or flag is malformed.
+func FromB3(ctx context.Context, header http.Header) (context.Context, error) {
+	var (
+		traceID, spanID, parentID, sampling string
+		err                                 error
+	)
+	if single := header.Get(B3Header); single != "" {
+		traceID, spanID, sampling, parentID, err = parseB3Single(single)
+	} else {
+		traceID = header.Get(B3TraceIDHeader)
+		spanID = header.Get(B3SpanIDHeader)
+		parentID = header.Get(B3ParentSpanIDHeader)
+		sampling = header.Get(B3SampledHeader)
+		if header.Get(B3FlagsHeader) == "1" {
+			sampling = "d"
+		}
+		if (traceID == "") != (spanID == "") {
+			err = errors.New("X-B3-TraceId and X-B3-SpanId must be sent together")
+		}
+	}
+	if err != nil {
+		return ctx, fmt.Errorf("%w: %v", ErrInvalidB3, err)
+	}
+
+	out := ctx
+	if traceID != "" {
+		traceID, spanID, parentID = strings.ToLower(traceID), strings.ToLower(spanID), strings.ToLower(parentID)
+		if len(traceID) == 16 {
+			traceID = strings.Repeat("0", 16) + traceID
+		}
+		switch {
+		case !isValidID(traceID, 32):
+			return ctx, fmt.Errorf("%w: trace ID must be 16 or 32 hex digits, not all zero", ErrInvalidB3)
+		case !isValidID(spanID, 16):
+			return ctx, fmt.Errorf("%w: span ID must be 16 hex digits, not all zero", ErrInvalidB3)
+		case parentID != "" && !isValidID(parentID, 16):
+			return ctx, fmt.Errorf("%w: parent span ID must be 16 hex digits, not all zero", ErrInvalidB3)
+		}
+		out = internal.WithTraceID(out, traceID)
+		out = internal.WithSpanID(out, spanID)
+		out = internal.WithParentSpanID(out, parentID)
+		out = internal.WithoutTraceSampled(out)
+	}
+
+	switch sampling {
+	case "":
+	case "1", "true", "d":
+		out = internal.WithTraceSampled(out, true)
+	case "0", "false":
+		out = internal.WithTraceSampled(out, false)
+	default:
+		return ctx, fmt.Errorf("%w: unknown sampling state %q", ErrInvalidB3, sampling)
+	}
+	return out, nil
+}
+
+// InjectB3 writes the current trace of ctx as X-B3-* headers. Nothing is written
+// when the trace or span ID are not valid hex identifiers.
+func InjectB3(ctx context.Context, header http.Header) {
+	traceID, spanID, parentID, sampling, ok := b3Fields(ctx)
+	if !ok {
+		return
+	}
+	header.Set(B3TraceIDHeader, traceID)
+	header.Set(B3SpanIDHeader, spanID)
+	if parentID != "" {
+		header.Set(B3ParentSpanIDHeader, parentID)
+	}
+	if sampling != "" {
+		header.Set(B3SampledHeader, sampling)
+	}
+}
+
+// InjectB3Single writes the current trace of ctx as the single "b3" header
+// ("{trace}-{span}[-{sampled}[-{parent}]]"). Nothing is written when the trace or
+// span ID are not valid hex identifiers.
+func InjectB3Single(ctx context.Context, header http.Header) {
+	traceID, spanID, parentID, sampling, ok := b3Fields(ctx)
+	if !ok {
+		return
+	}
+	value := traceID + "-" + spanID
+	if sampling != "" {
+		value += "-" + sampling
+		if parentID != "" {
+			value += "-" + parentID
+		}
+	}
+	header.Set(B3Header, value)
+}
+
+func b3Fields(ctx context.Context) (traceID, spanID, parentID, sampling string, ok bool) {
+	traceID = internal.ExtractTraceID(ctx)
+	spanID = internal.ExtractSpanID(ctx)
+	if !(isValidID(traceID, 32) || isValidID(traceID, 16)) || !isValidID(spanID, 16) {
+		return "", "", "", "", false
+	}
+
+	if parent := internal.ExtractParentSpanID(ctx); isValidID(parent, 16) {
+		parentID = parent
+	}
+	if sampled, known := internal.ExtractTraceSampled(ctx); known {
+		sampling = "0"
+		if sampled {
+			sampling = "1"
+		}
+	}
+	return traceID, spanID, parentID, sampling, true
+}
+
+// parseB3Single splits "{trace}-{span}[-{sampling}[-{parent}]]" or a lone "{sampling}".
+func parseB3Single(value string) (traceID, spanID, sampling, parentID string, err error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	switch len(parts) {
+	case 1:
+		return "", "", parts[0], "", nil
+	case 2:
+		return parts[0], parts[1], "", "", nil
+	case 3:
+		return parts[0], parts[1], parts[2], "", nil
+	case 4:
+		return parts[0], parts[1], parts[2], parts[3], nil
+	default:
+		return "", "", "", "", fmt.Errorf("malformed b3 header %q", value)
+	}
+}
@@ -0,0 +1,122 @@
+package ctx_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	chronologctx "github.com/Astronotify/chronolog/ctx"
+	"github.com/Astronotify/chronolog/internal"
+)
+
+func TestFromB3MultiHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set(chronologctx.B3TraceIDHeader, "a3ce929d0e0e4736")
+	header.Set(chronologctx.B3SpanIDHeader, "00f067aa0ba902b7")
+	header.Set(chronologctx.B3ParentSpanIDHeader, "00000000000004d2")
+	header.Set(chronologctx.B3SampledHeader, "1")
+
+	ctx, err := chronologctx.FromB3(context.Background(), header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := internal.ExtractTraceID(ctx); got != "0000000000000000a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %q", got)
+	}
+	if got := internal.ExtractSpanID(ctx); got != "00f067aa0ba902b7" {
+		t.Errorf("span ID = %q", got)
+	}
+	if got := internal.ExtractParentSpanID(ctx); got != "00000000000004d2" {
+		t.Errorf("parent span ID = %q", got)
+	}
+	if sampled, ok := chronologctx.TraceSampled(ctx); !sampled || !ok {
+		t.Errorf("TraceSampled() = %v, %v", sampled, ok)
+	}
+	if got := chronologctx.Traceparent(ctx); got != "00-0000000000000000a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("Traceparent() = %q", got)
+	}
+
+	out := http.Header{}
+	chronologctx.InjectB3(ctx, out)
+	if got := out.Get(chronologctx.B3TraceIDHeader); got != "0000000000000000a3ce929d0e0e4736" {
+		t.Errorf("injected trace ID = %q", got)
+	}
+	if got := out.Get(chronologctx.B3ParentSpanIDHeader); got != "00000000000004d2" {
+		t.Errorf("injected parent span ID = %q", got)
+	}
+	if got := out.Get(chronologctx.B3SampledHeader); got != "1" {
+		t.Errorf("injected sampled = %q", got)
+	}
+}
+
+func TestFromB3SingleHeader(t *testing.T) {
+	const value = "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-d-05e3ac9a4f6e3b90"
+
+	ctx, err := chronologctx.FromB3(context.Background(), http.Header{"B3": {value}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := internal.ExtractParentSpanID(ctx); got != "05e3ac9a4f6e3b90" {
+		t.Errorf("parent span ID = %q", got)
+	}
+	if sampled, ok := chronologctx.TraceSampled(ctx); !sampled || !ok {
+		t.Errorf("debug TraceSampled() = %v, %v", sampled, ok)
+	}
+
+	out := http.Header{}
+	chronologctx.InjectB3Single(ctx, out)
+	if got := out.Get(chronologctx.B3Header); got != "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90" {
+		t.Errorf("InjectB3Single() = %q", got)
+	}
+
+	stale := chronologctx.WithParentSpanID(context.Background(), "b7ad6b7169203331")
+	stale = chronologctx.WithTraceSampled(stale, true)
+	root, err := chronologctx.FromB3(stale, http.Header{"B3": {"80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := internal.ExtractParentSpanID(root); got != "" {
+		t.Errorf("stale parent span ID = %q", got)
+	}
+	if sampled, ok := chronologctx.TraceSampled(root); ok {
+		t.Errorf("stale TraceSampled() = %v, %v", sampled, ok)
+	}
+
+	denied, err := chronologctx.FromB3(context.Background(), http.Header{"B3": {"0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sampled, ok := chronologctx.TraceSampled(denied); sampled || !ok {
+		t.Errorf("sampling-only TraceSampled() = %v, %v", sampled, ok)
+	}
+	if got := internal.ExtractTraceID(denied); got != "" {
+		t.Errorf("sampling-only trace ID = %q", got)
+	}
+}
+
+func TestFromB3Validation(t *testing.T) {
+	for _, value := range []string{
+		"80f198ee56343ba8-zz57b5a2e4d86bd1",
+		"80f198ee56343ba8",
+		"0000000000000000-e457b5a2e4d86bd1",
+		"80f198ee56343ba8-e457b5a2e4d86bd1-2",
+		"80f198ee56343ba8-e457b5a2e4d86bd1-1-123",
+		"80f198ee56343ba8-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90-x",
+	} {
+		if _, err := chronologctx.FromB3(context.Background(), http.Header{"B3": {value}}); !errors.Is(err, chronologctx.ErrInvalidB3) {
+			t.Errorf("FromB3(%q) error = %v", value, err)
+		}
+	}
+
+	header := http.Header{}
+	header.Set(chronologctx.B3TraceIDHeader, "80f198ee56343ba8")
+	if _, err := chronologctx.FromB3(context.Background(), header); !errors.Is(err, chronologctx.ErrInvalidB3) {
+		t.Errorf("FromB3() without span ID error = %v", err)
+	}
+
+	ctx, err := chronologctx.FromB3(context.Background(), http.Header{})
+	if err != nil || ctx != context.Background() {
+		t.Errorf("FromB3() without headers = %v, %v", ctx, err)
+	}
+}
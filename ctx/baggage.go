@@ -0,0 +1,192 @@
+package ctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/Astronotify/chronolog/internal"
+)
+
+// BaggageHeader is the W3C Baggage header name.
+const BaggageHeader = "baggage"
+
+// Limits of the W3C Baggage specification.
+const (
+	maxBaggageMembers = 64
+	maxBaggageBytes   = 8192
+)
+
+// ErrInvalidBaggage is returned for baggage headers that do not follow the W3C
+// Baggage format, and for invalid baggage keys.
+var ErrInvalidBaggage = errors.New("invalid baggage")
+
+// FromBaggage parses a W3C baggage header ("key=value;property,...") and stores
+// its members, replacing the baggage already in ctx. Values are percent-decoded;
+// properties are kept verbatim. Empty list members are dropped and, when a key is
+// repeated, the last value wins.
+//
+// Parameters:
+//   - ctx (context.Context): the parent context.
+//   - header (string): the baggage header value, possibly combined from several headers.
+//
+// Returns:
+//   - context.Context: the context carrying the baggage, or ctx unchanged on error.
+//   - error: an error wrapping ErrInvalidBaggage if a member is malformed or the
+//     header exceeds 64 members or 8192 bytes.
+func FromBaggage(ctx context.Context, header string) (context.Context, error) {
+	if len(header) > maxBaggageBytes {
+		return ctx, fmt.Errorf("%w: %d bytes, at most %d allowed", ErrInvalidBaggage, len(header), maxBaggageBytes)
+	}
+
+	var members []internal.BaggageMember
+	for _, raw := range strings.Split(header, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		member, err := parseBaggageMember(raw)
+		if err != nil {
+			return ctx, fmt.Errorf("%w: %v", ErrInvalidBaggage, err)
+		}
+		members = setBaggageMember(members, member)
+	}
+	if len(members) > maxBaggageMembers {
+		return ctx, fmt.Errorf("%w: %d members, at most %d allowed", ErrInvalidBaggage, len(members), maxBaggageMembers)
+	}
+	return internal.WithBaggage(ctx, members), nil
+}
+
+// Baggage returns the baggage header propagating the baggage of ctx, or "".
+// Values are percent-encoded. Members that would exceed the limits of 64 members
+// or 8192 bytes are dropped.
+func Baggage(ctx context.Context) string {
+	var b strings.Builder
+	count := 0
+	for _, member := range internal.ExtractBaggage(ctx) {
+		encoded := member.Key + "=" + encodeBaggageValue(member.Value)
+		if member.Properties != "" {
+			encoded += ";" + member.Properties
+		}
+
+		size := len(encoded)
+		if count > 0 {
+			size++ // separator
+		}
+		if count == maxBaggageMembers || b.Len()+size > maxBaggageBytes {
+			continue
+		}
+		if count > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(encoded)
+		count++
+	}
+	return b.String()
+}
+
+// WithBaggageValue returns a context whose baggage carries value for key,
+// replacing any previous value and its properties.
+//
+// Parameters:
+//   - ctx (context.Context): the parent context.
+//   - key (string): the baggage key, an HTTP token such as "customer_tier".
+//   - value (string): the value, percent-encoded when propagated.
+//
+// Returns:
+//   - context.Context: the context carrying the updated baggage, or ctx unchanged on error.
+//   - error: an error wrapping ErrInvalidBaggage if key is not a valid token.
+func WithBaggageValue(ctx context.Context, key, value string) (context.Context, error) {
+	if !isToken(key) {
+		return ctx, fmt.Errorf("%w: malformed key %q", ErrInvalidBaggage, key)
+	}
+	members := slices.Clone(internal.ExtractBaggage(ctx))
+	members = setBaggageMember(members, internal.BaggageMember{Key: key, Value: value})
+	return internal.WithBaggage(ctx, members), nil
+}
+
+// BaggageValue returns the decoded value of key in the baggage of ctx, and false
+// if the key is absent.
+func BaggageValue(ctx context.Context, key string) (string, bool) {
+	for _, member := range internal.ExtractBaggage(ctx) {
+		if member.Key == key {
+			return member.Value, true
+		}
+	}
+	return "", false
+}
+
+// setBaggageMember replaces the member with the same key, or appends member.
+func setBaggageMember(members []internal.BaggageMember, member internal.BaggageMember) []internal.BaggageMember {
+	for i := range members {
+		if members[i].Key == member.Key {
+			members[i] = member
+			return members
+		}
+	}
+	return append(members, member)
+}
+
+func parseBaggageMember(raw string) (internal.BaggageMember, error) {
+	pair, properties, _ := strings.Cut(raw, ";")
+	key, value, ok := strings.Cut(pair, "=")
+	key = strings.TrimSpace(key)
+	value = strings.TrimSpace(value)
+	if !ok || !isToken(key) || !isBaggageValue(value) {
+		return internal.BaggageMember{}, fmt.Errorf("malformed member %q", raw)
+	}
+
+	decoded, err := url.PathUnescape(value)
+	if err != nil {
+		return internal.BaggageMember{}, fmt.Errorf("malformed percent-encoding in %q", raw)
+	}
+	return internal.BaggageMember{Key: key, Value: decoded, Properties: strings.TrimSpace(properties)}, nil
+}
+
+func encodeBaggageValue(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if isBaggageOctet(c) && c != '%' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// isBaggageValue reports whether value only has baggage-octets, '%' included for
+// percent-encoding.
+func isBaggageValue(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if !isBaggageOctet(value[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isBaggageOctet reports whether c may appear unencoded in a value: printable
+// US-ASCII except space, '"', ',', ';' and '\'.
+func isBaggageOctet(c byte) bool {
+	return c > ' ' && c <= '~' && c != '"' && c != ',' && c != ';' && c != '\\'
+}
+
+// isToken reports whether s is an RFC 7230 token.
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		alnum := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+		if !alnum && !strings.ContainsRune("!#$%&'*+-.^_`|~", rune(c)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package ctx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	chronologctx "github.com/Astronotify/chronolog/ctx"
+)
+
+func TestBaggageRoundTrip(t *testing.T) {
+	const header = " userId=alice%20smith , serverNode=DF%2028;ttl=60,, isProduction=false,userId=bob%2Cjr "
+
+	ctx, err := chronologctx.FromBaggage(context.Background(), header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := chronologctx.BaggageValue(ctx, "userId"); !ok || v != "bob,jr" {
+		t.Errorf("userId = %q, %v", v, ok)
+	}
+	if v, _ := chronologctx.BaggageValue(ctx, "serverNode"); v != "DF 28" {
+		t.Errorf("serverNode = %q", v)
+	}
+	if _, ok := chronologctx.BaggageValue(ctx, "missing"); ok {
+		t.Error("missing key reported as present")
+	}
+	if got := chronologctx.Baggage(ctx); got != "userId=bob%2Cjr,serverNode=DF%2028;ttl=60,isProduction=false" {
+		t.Errorf("Baggage() = %q", got)
+	}
+
+	updated, err := chronologctx.WithBaggageValue(ctx, "tier", "gold 100%")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := chronologctx.Baggage(updated); !strings.HasSuffix(got, ",tier=gold%20100%25") {
+		t.Errorf("Baggage() after WithBaggageValue = %q", got)
+	}
+	if _, ok := chronologctx.BaggageValue(ctx, "tier"); ok {
+		t.Error("WithBaggageValue modified the parent context")
+	}
+	if _, err := chronologctx.WithBaggageValue(ctx, "bad key", "v"); !errors.Is(err, chronologctx.ErrInvalidBaggage) {
+		t.Errorf("invalid key error = %v", err)
+	}
+}
+
+func TestFromBaggageValidation(t *testing.T) {
+	many := make([]string, 65)
+	for i := range many {
+		many[i] = fmt.Sprintf("k%d=v", i)
+	}
+
+	for _, header := range []string{
+		"novalue",
+		"=value",
+		"key=va lue",
+		`key="quoted"`,
+		"key=%zz",
+		strings.Join(many, ","),
+		"key=" + strings.Repeat("v", 8192),
+	} {
+		if _, err := chronologctx.FromBaggage(context.Background(), header); !errors.Is(err, chronologctx.ErrInvalidBaggage) {
+			t.Errorf("FromBaggage(%.40q) error = %v", header, err)
+		}
+	}
+}
+
+func TestBaggageDropsMembersOverLimits(t *testing.T) {
+	ctx := context.Background()
+	var err error
+	for i := 0; i < 70; i++ {
+		if ctx, err = chronologctx.WithBaggageValue(ctx, fmt.Sprintf("k%d", i), "v"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := strings.Count(chronologctx.Baggage(ctx), ","); got != 63 {
+		t.Errorf("serialized %d members, want 64", got+1)
+	}
+
+	big, _ := chronologctx.WithBaggageValue(context.Background(), "big", strings.Repeat("x", 8000))
+	big, _ = chronologctx.WithBaggageValue(big, "large", strings.Repeat("y", 500))
+	big, _ = chronologctx.WithBaggageValue(big, "small", "z")
+	if got := chronologctx.Baggage(big); len(got) > 8192 || strings.Contains(got, "large=") || !strings.HasSuffix(got, ",small=z") {
+		t.Errorf("Baggage() over the size limit = %d bytes", len(got))
+	}
+}
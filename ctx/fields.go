@@ -0,0 +1,75 @@
+package ctx
+
+import (
+	"context"
+
+	"github.com/Astronotify/chronolog/internal"
+)
+
+// WithFields stores correlation fields (tenant ID, user ID, region...) reported in
+// the "context" object of every entry logged with the returned context.
+//
+// Fields accumulate across nested contexts: the returned context carries the
+// fields of ctx plus the given ones, which win on conflicting keys.
+//
+// Parameters:
+//   - ctx (context.Context): the parent context.
+//   - fields (map[string]any): the fields to add. The map is copied.
+//
+// Returns:
+//   - context.Context: the context carrying the accumulated fields.
+func WithFields(ctx context.Context, fields map[string]any) context.Context {
+	return internal.WithFields(ctx, fields)
+}
+
+// Fields returns a copy of the fields that entries logged with ctx report in
+// their "context" object, including the values of typed keys, or nil if none.
+func Fields(ctx context.Context) map[string]any {
+	return internal.ExtractContextFields(ctx)
+}
+
+// Key is a typed context key whose value, when set, is reported under its name in
+// the "context" object of every entry. Create keys with NewKey.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey creates and registers a typed context key, usually as a package-level
+// variable:
+//
+//	var TenantID = chronologctx.NewKey[string]("tenant_id")
+//
+//	ctx = TenantID.WithValue(ctx, "acme")
+//
+// Values of typed keys take precedence over WithFields fields of the same name.
+//
+// Parameters:
+//   - name (string): the field name reported in entries.
+//
+// Returns:
+//   - *Key[T]: the registered key.
+//
+// NewKey panics if name is empty or already used by another key.
+func NewKey[T any](name string) *Key[T] {
+	k := &Key[T]{name: name}
+	internal.RegisterContextField(name, func(ctx context.Context) (any, bool) {
+		return k.Value(ctx)
+	})
+	return k
+}
+
+// Name returns the field name of the key.
+func (k *Key[T]) Name() string {
+	return k.name
+}
+
+// WithValue returns a context carrying value for the key.
+func (k *Key[T]) WithValue(ctx context.Context, value T) context.Context {
+	return context.WithValue(ctx, k, value)
+}
+
+// Value returns the value of the key in ctx, and false if it is not set.
+func (k *Key[T]) Value(ctx context.Context) (T, bool) {
+	value, ok := ctx.Value(k).(T)
+	return value, ok
+}
@@ -0,0 +1,66 @@
+package ctx_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	chronologctx "github.com/Astronotify/chronolog/ctx"
+	"github.com/Astronotify/chronolog/entries"
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+var (
+	regionKey = chronologctx.NewKey[string]("region")
+	cohortKey = chronologctx.NewKey[int]("cohort")
+)
+
+func TestWithFieldsAccumulates(t *testing.T) {
+	outer := chronologctx.WithFields(context.Background(), map[string]any{"tenant_id": "acme", "user_id": "u-1"})
+	inner := chronologctx.WithFields(outer, map[string]any{"user_id": "u-2", "session_id": "s-9"})
+
+	want := map[string]any{"tenant_id": "acme", "user_id": "u-2", "session_id": "s-9"}
+	if got := chronologctx.Fields(inner); !reflect.DeepEqual(got, want) {
+		t.Errorf("inner Fields() = %v", got)
+	}
+	if got := chronologctx.Fields(outer); got["user_id"] != "u-1" || len(got) != 2 {
+		t.Errorf("outer Fields() = %v", got)
+	}
+	if got := chronologctx.Fields(context.Background()); got != nil {
+		t.Errorf("empty Fields() = %v", got)
+	}
+}
+
+func TestTypedKeysLiftedIntoEntries(t *testing.T) {
+	ctx := chronologctx.WithFields(context.Background(), map[string]any{"region": "from-fields", "tenant_id": "acme"})
+	ctx = regionKey.WithValue(ctx, "eu-west-1")
+	ctx = cohortKey.WithValue(ctx, 3)
+
+	if region, ok := regionKey.Value(ctx); !ok || region != "eu-west-1" {
+		t.Errorf("Value() = %q, %v", region, ok)
+	}
+
+	entry := entries.NewLogEntry(ctx, Level.Info, "hello")
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"context":{"cohort":3,"region":"eu-west-1","tenant_id":"acme"}`) {
+		t.Errorf("unexpected entry: %s", data)
+	}
+
+	if data, _ := json.Marshal(entries.NewLogEntry(context.Background(), Level.Info, "plain")); strings.Contains(string(data), `"context"`) {
+		t.Errorf("empty context serialized: %s", data)
+	}
+}
+
+func TestNewKeyRejectsDuplicateNames(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a duplicate key name")
+		}
+	}()
+	chronologctx.NewKey[bool]("region")
+}
@@ -0,0 +1,225 @@
+package ctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Astronotify/chronolog/internal"
+)
+
+// W3C Trace Context header names.
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+// sampledFlag is the trace-flags bit carrying the sampling decision.
+const sampledFlag = 0x01
+
+// maxTracestateMembers is the maximum number of list members in tracestate.
+const maxTracestateMembers = 32
+
+var (
+	// ErrInvalidTraceparent is returned for traceparent headers that do not follow
+	// the W3C Trace Context format.
+	ErrInvalidTraceparent = errors.New("invalid traceparent")
+
+	// ErrInvalidTracestate is returned for tracestate headers that do not follow
+	// the W3C Trace Context format.
+	ErrInvalidTracestate = errors.New("invalid tracestate")
+)
+
+// FromTraceparent stores the trace ID, the span ID and the sampled flag of a W3C
+// traceparent header ("00-<32 hex trace-id>-<16 hex parent-id>-<2 hex flags>").
+//
+// The caller's span (parent-id) becomes the current span of ctx, as the remote
+// parent of the work done locally. Any parent span ID already in ctx is cleared,
+// since it belongs to a different trace.
+//
+// Parameters:
+//   - ctx (context.Context): the parent context.
+//   - header (string): the traceparent header value.
+//
+// Returns:
+//   - context.Context: the context carrying the trace, or ctx unchanged on error.
+//   - error: an error wrapping ErrInvalidTraceparent if the header is malformed,
+//     uses version ff, or carries an all-zero trace or parent ID.
+func FromTraceparent(ctx context.Context, header string) (context.Context, error) {
+	traceID, spanID, flags, err := parseTraceparent(strings.TrimSpace(header))
+	if err != nil {
+		return ctx, fmt.Errorf("%w: %v", ErrInvalidTraceparent, err)
+	}
+
+	ctx = internal.WithTraceID(ctx, traceID)
+	ctx = internal.WithSpanID(ctx, spanID)
+	ctx = internal.WithParentSpanID(ctx, "")
+	return internal.WithTraceSampled(ctx, flags&sampledFlag != 0), nil
+}
+
+// Traceparent returns the traceparent header propagating the current trace and
+// span of ctx, or "" when they are missing or not valid W3C identifiers.
+// The sampled flag is set only when a positive sampling decision is stored.
+func Traceparent(ctx context.Context) string {
+	traceID := internal.ExtractTraceID(ctx)
+	spanID := internal.ExtractSpanID(ctx)
+	if !isValidID(traceID, 32) || !isValidID(spanID, 16) {
+		return ""
+	}
+
+	flags := "00"
+	if sampled, _ := internal.ExtractTraceSampled(ctx); sampled {
+		flags = "01"
+	}
+	return "00-" + traceID + "-" + spanID + "-" + flags
+}
+
+// WithTraceSampled stores the sampling decision of the current trace.
+func WithTraceSampled(ctx context.Context, sampled bool) context.Context {
+	return internal.WithTraceSampled(ctx, sampled)
+}
+
+// TraceSampled returns the sampling decision of the current trace. The second
+// value is false when no decision was propagated.
+func TraceSampled(ctx context.Context) (sampled bool, ok bool) {
+	return internal.ExtractTraceSampled(ctx)
+}
+
+// FromTracestate validates a W3C tracestate header and stores it so that it can
+// be propagated with Tracestate. Empty list members are dropped.
+//
+// Parameters:
+//   - ctx (context.Context): the parent context.
+//   - header (string): the tracestate header value, possibly combined from several headers.
+//
+// Returns:
+//   - context.Context: the context carrying the trace state, or ctx unchanged on error.
+//   - error: an error wrapping ErrInvalidTracestate if a member is malformed, a key
+//     is repeated or there are more than 32 members.
+func FromTracestate(ctx context.Context, header string) (context.Context, error) {
+	normalized, err := normalizeTracestate(header)
+	if err != nil {
+		return ctx, fmt.Errorf("%w: %v", ErrInvalidTracestate, err)
+	}
+	return internal.WithTraceState(ctx, normalized), nil
+}
+
+// Tracestate returns the tracestate stored by FromTracestate, or "".
+func Tracestate(ctx context.Context) string {
+	return internal.ExtractTraceState(ctx)
+}
+
+func parseTraceparent(header string) (traceID, spanID string, flags byte, err error) {
+	// version "00" has exactly four fields; later versions may append more
+	if len(header) < 55 || (len(header) > 55 && header[55] != '-') {
+		return "", "", 0, errors.New("unexpected length")
+	}
+	if header[2] != '-' || header[35] != '-' || header[52] != '-' {
+		return "", "", 0, errors.New("unexpected field separators")
+	}
+
+	version := header[0:2]
+	switch {
+	case !isLowerHex(version):
+		return "", "", 0, errors.New("version is not hex")
+	case version == "ff":
+		return "", "", 0, errors.New("version ff is forbidden")
+	case version == "00" && len(header) != 55:
+		return "", "", 0, errors.New("unexpected data after version 00 fields")
+	}
+
+	traceID, spanID = header[3:35], header[36:52]
+	if !isValidID(traceID, 32) {
+		return "", "", 0, errors.New("trace-id must be 32 lowercase hex digits, not all zero")
+	}
+	if !isValidID(spanID, 16) {
+		return "", "", 0, errors.New("parent-id must be 16 lowercase hex digits, not all zero")
+	}
+
+	f, err := strconv.ParseUint(header[53:55], 16, 8)
+	if err != nil || !isLowerHex(header[53:55]) {
+		return "", "", 0, errors.New("trace-flags is not hex")
+	}
+	return traceID, spanID, byte(f), nil
+}
+
+func normalizeTracestate(header string) (string, error) {
+	var members []string
+	seen := map[string]bool{}
+	for _, member := range strings.Split(header, ",") {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(member, "=")
+		if !ok || !isTracestateKey(key) || !isTracestateValue(value) {
+			return "", fmt.Errorf("malformed member %q", member)
+		}
+		if seen[key] {
+			return "", fmt.Errorf("duplicate key %q", key)
+		}
+		seen[key] = true
+		members = append(members, member)
+	}
+	if len(members) > maxTracestateMembers {
+		return "", fmt.Errorf("%d members, at most %d allowed", len(members), maxTracestateMembers)
+	}
+	return strings.Join(members, ","), nil
+}
+
+// isTracestateKey validates simple keys ("vendor") and multi-tenant keys ("tenant@vendor").
+func isTracestateKey(key string) bool {
+	if tenant, system, ok := strings.Cut(key, "@"); ok {
+		return len(tenant) >= 1 && len(tenant) <= 241 && isKeyChars(tenant, true) &&
+			len(system) >= 1 && len(system) <= 14 && isKeyChars(system, false)
+	}
+	return len(key) >= 1 && len(key) <= 256 && isKeyChars(key, false)
+}
+
+func isKeyChars(s string, digitFirst bool) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		lower := c >= 'a' && c <= 'z'
+		digit := c >= '0' && c <= '9'
+		if i == 0 {
+			if !lower && !(digitFirst && digit) {
+				return false
+			}
+			continue
+		}
+		if !lower && !digit && c != '_' && c != '-' && c != '*' && c != '/' {
+			return false
+		}
+	}
+	return true
+}
+
+func isTracestateValue(value string) bool {
+	if len(value) == 0 || len(value) > 256 || value[len(value)-1] == ' ' {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c < 0x20 || c > 0x7e || c == ',' || c == '=' {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidID reports whether id is n lowercase hex digits, not all zero.
+func isValidID(id string, n int) bool {
+	return len(id) == n && isLowerHex(id) && strings.Trim(id, "0") != ""
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package ctx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	chronologctx "github.com/Astronotify/chronolog/ctx"
+	"github.com/Astronotify/chronolog/internal"
+)
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	const header = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	stale := chronologctx.WithParentSpanID(context.Background(), "b7ad6b7169203331")
+	ctx, err := chronologctx.FromTraceparent(stale, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := internal.ExtractParentSpanID(ctx); got != "" {
+		t.Errorf("stale parent span ID = %q", got)
+	}
+	if got := internal.ExtractTraceID(ctx); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %q", got)
+	}
+	if got := internal.ExtractSpanID(ctx); got != "00f067aa0ba902b7" {
+		t.Errorf("span ID = %q", got)
+	}
+	if sampled, ok := chronologctx.TraceSampled(ctx); !sampled || !ok {
+		t.Errorf("TraceSampled() = %v, %v", sampled, ok)
+	}
+	if got := chronologctx.Traceparent(ctx); got != header {
+		t.Errorf("Traceparent() = %q", got)
+	}
+
+	unsampled := chronologctx.WithTraceSampled(ctx, false)
+	if got := chronologctx.Traceparent(unsampled); !strings.HasSuffix(got, "-00") {
+		t.Errorf("unsampled Traceparent() = %q", got)
+	}
+	if got := chronologctx.Traceparent(chronologctx.WithTraceID(context.Background(), "opaque")); got != "" {
+		t.Errorf("Traceparent() with an opaque ID = %q", got)
+	}
+}
+
+func TestFromTraceparentValidation(t *testing.T) {
+	for _, header := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0x",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		ctx := context.Background()
+		got, err := chronologctx.FromTraceparent(ctx, header)
+		if !errors.Is(err, chronologctx.ErrInvalidTraceparent) || got != ctx {
+			t.Errorf("%q: err = %v", header, err)
+		}
+	}
+
+	// later versions may append fields
+	if _, err := chronologctx.FromTraceparent(context.Background(),
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future"); err != nil {
+		t.Errorf("future version rejected: %v", err)
+	}
+}
+
+func TestTracestate(t *testing.T) {
+	ctx, err := chronologctx.FromTracestate(context.Background(), "rojo=00f067aa0ba902b7, ,tenant@congo=t61rcWkgMzE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := chronologctx.Tracestate(ctx); got != "rojo=00f067aa0ba902b7,tenant@congo=t61rcWkgMzE" {
+		t.Errorf("Tracestate() = %q", got)
+	}
+
+	for _, header := range []string{
+		"Rojo=1",
+		"rojo=1,rojo=2",
+		"rojo",
+		"rojo=a,b",
+		"rojo=" + strings.Repeat("x", 257),
+		tooManyMembers(),
+	} {
+		if _, err := chronologctx.FromTracestate(context.Background(), header); !errors.Is(err, chronologctx.ErrInvalidTracestate) {
+			t.Errorf("%q: err = %v", header, err)
+		}
+	}
+}
+
+func tooManyMembers() string {
+	members := make([]string, 33)
+	for i := range members {
+		members[i] = fmt.Sprintf("k%d=v", i)
+	}
+	return strings.Join(members, ",")
+}
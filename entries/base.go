@@ -22,12 +22,19 @@ import (
 //   - EventType: a string label that categorizes the type of log entry.
 //     Defaults to "LogEntry", but can be overridden by embedding structs.
 //   - Message: the human-readable message describing the event or situation.
+//   - LoggerName: the name of the named logger that emitted the entry (see chronolog.Named), if any.
+//   - ContextFields: correlation fields carried by the context (see ctx.WithFields and ctx.NewKey),
+//     serialized as the "context" object.
+//   - Baggage: the W3C baggage members of the logging context whose keys are allowlisted
+//     in chronolog.Config.BaggageKeys. Set by the logger when the entry is written.
 //
 // Trace fields:
 //
 //   - TraceID: unique identifier for the current trace, if available.
 //   - SpanID: identifier for the current span within the trace.
 //   - ParentSpanID: identifier of the parent span, if applicable.
+//   - TraceSampled: the sampling decision propagated with the trace (e.g. the traceparent
+//     sampled flag), or nil when none was propagated.
 //
 // Build information:
 //
@@ -54,10 +61,15 @@ type LogEntry struct {
 	EventType string         `json:"event_type"`
 	Message   string         `json:"message"`
 
+	LoggerName    string            `json:"logger,omitempty"`
+	ContextFields map[string]any    `json:"context,omitempty"`
+	Baggage       map[string]string `json:"baggage,omitempty"`
+
 	// Trace metadata
 	TraceID      string `json:"trace_id,omitempty"`
 	SpanID       string `json:"span_id,omitempty"`
 	ParentSpanID string `json:"parent_span_id,omitempty"`
+	TraceSampled *bool  `json:"trace_sampled,omitempty"`
 
 	// Build information
 	Version    string `json:"version,omitempty"`
@@ -98,9 +110,11 @@ func NewLogEntry(ctx context.Context, level Level.LogLevel, message string, addi
 		Level:            level,
 		EventType:        "LogEntry",
 		Message:          message,
+		ContextFields:    internal.ExtractContextFields(ctx),
 		TraceID:          internal.ExtractTraceID(ctx),
 		SpanID:           internal.ExtractSpanID(ctx),
 		ParentSpanID:     internal.ExtractParentSpanID(ctx),
+		TraceSampled:     extractTraceSampled(ctx),
 		Version:          internal.ExtractVersion(ctx),
 		CommitHash:       internal.ExtractCommitHash(ctx),
 		BuildTime:        internal.ExtractBuildTime(ctx),
@@ -115,3 +129,18 @@ func NewLogEntry(ctx context.Context, level Level.LogLevel, message string, addi
 func (l LogEntry) GetLevel() Level.LogLevel {
 	return l.Level
 }
+
+func (l LogEntry) GetEventType() string {
+	return l.EventType
+}
+
+func (l LogEntry) GetLoggerName() string {
+	return l.LoggerName
+}
+
+func extractTraceSampled(ctx context.Context) *bool {
+	if sampled, ok := internal.ExtractTraceSampled(ctx); ok {
+		return &sampled
+	}
+	return nil
+}
@@ -7,6 +7,12 @@ import (
 	Level "github.com/Astronotify/chronolog/level"
 )
 
+// StackFrame is one frame of the stack trace reported in ErrorLogEntry.
+type StackFrame = internal.StackFrame
+
+// ErrorCause describes one error of the causal chain reported in ErrorLogEntry.
+type ErrorCause = internal.ErrorCause
+
 // ErrorLogEntry represents a structured log entry dedicated to error events.
 //
 // It extends LogEntry by adding metadata specific to runtime errors, enabling better diagnostics,
@@ -14,16 +20,28 @@ import (
 //
 // Fields:
 //
-//   - ErrorClass: a short identifier that classifies the type of error (e.g., "ValidationError", "IOError").
-//     Derived via internal heuristics or conventions.
+//   - ErrorClass: a short identifier that classifies the type of error (e.g., "Timeout", "NotExist").
+//     Taken from a ChronologClass() string method in the error chain, a registered classifier,
+//     a well-known sentinel, or the concrete type of the root error, in that order.
+//   - ErrorType: the concrete Go type of the logged (outermost) error, e.g. "*fmt.wrapError".
+//   - RootErrorType: the concrete Go type of the innermost error of the Unwrap chain, e.g. "*fs.PathError".
 //   - ErrorMessage: the error message returned by the error object. Also used as the main log message.
-//   - StackTrace: an optional string representation of the stack trace where the error occurred.
-//     Included only if available and supported by the error type or logger configuration.
+//   - Causes: every error of the Unwrap chain, outermost first, with its type, message and class.
+//     Multi-errors (errors.Join, Unwrap() []error) end the chain and list each wrapped chain in Branches.
+//   - Fingerprint: a stable hash of ErrorClass, the message with numbers, hex IDs and UUIDs masked,
+//     and the normalized top stack frames. Occurrences of the same failure share a fingerprint.
+//   - StackTrace: the frames (function, file, line) where the error was created, taken from the
+//     deepest error exposing a StackTrace() method (see chronolog/errors). Falls back to the
+//     stack of the logging call. Chronolog frames are trimmed.
 type ErrorLogEntry struct {
 	LogEntry
-	ErrorClass   string `json:"error_class"`
-	ErrorMessage string `json:"error_message"`
-	StackTrace   string `json:"stack_trace,omitempty"`
+	ErrorClass    string       `json:"error_class"`
+	ErrorType     string       `json:"error_type,omitempty"`
+	RootErrorType string       `json:"root_error_type,omitempty"`
+	ErrorMessage  string       `json:"error_message"`
+	Causes        []ErrorCause `json:"causes,omitempty"`
+	Fingerprint   string       `json:"fingerprint"`
+	StackTrace    []StackFrame `json:"stack_trace,omitempty"`
 }
 
 // NewErrorLogEntry creates a new ErrorLogEntry from a given error and optional metadata.
@@ -48,10 +66,14 @@ func NewErrorLogEntry(ctx context.Context, err error, additionalData ...map[stri
 			err.Error(),
 			internal.MergeAdditionalData(additionalData...),
 		),
-		ErrorClass:   internal.ClassifyError(err),
-		ErrorMessage: err.Error(),
-		StackTrace:   internal.ExtractStackTrace(err),
+		ErrorClass:    internal.ClassifyError(err),
+		ErrorType:     internal.ErrorTypeName(err),
+		RootErrorType: internal.ErrorTypeName(internal.RootError(err)),
+		ErrorMessage:  err.Error(),
+		Causes:        internal.ExtractCauses(err),
+		StackTrace:    internal.ExtractStackTrace(err),
 	}
+	entry.Fingerprint = internal.Fingerprint(entry.ErrorClass, entry.ErrorMessage, entry.StackTrace)
 	entry.EventType = "ErrorLogEntry"
 	return entry
 }
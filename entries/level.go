@@ -0,0 +1,60 @@
+package entries
+
+import (
+	"context"
+	"time"
+
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+// LevelChangeLogEntry is an audit entry recording a runtime change of a minimum log level.
+//
+// It is created at Warn so that it reaches sinks under usual production thresholds.
+//
+// Fields:
+//
+//   - TargetLogger: the logger name prefix whose level changed, or empty for the global minimum level.
+//   - PreviousLevel: the level in effect before the change, or empty if the logger had no override.
+//   - NewLevel: the level in effect after the change, or empty if the override was removed.
+//   - Source: what triggered the change (e.g. "admin", "signal", "expired").
+//   - ExpiresAt: when a time-boxed change reverts automatically, if it does.
+type LevelChangeLogEntry struct {
+	LogEntry
+	TargetLogger  string         `json:"target_logger,omitempty"`
+	PreviousLevel Level.LogLevel `json:"previous_level"`
+	NewLevel      Level.LogLevel `json:"new_level"`
+	Source        string         `json:"source"`
+	ExpiresAt     *time.Time     `json:"expires_at,omitempty"`
+}
+
+// NewLevelChangeLogEntry creates an audit entry for a minimum level change.
+//
+// Parameters:
+//
+//   - ctx (context.Context): context used to enrich the log with trace/build info.
+//   - target (string): the logger name prefix, or "" for the global minimum level.
+//   - previous (Level.LogLevel): the level before the change.
+//   - next (Level.LogLevel): the level after the change.
+//   - source (string): what triggered the change.
+//   - additionalData (...map[string]any): optional metadata to enrich the log.
+//
+// Returns:
+//
+//   - LevelChangeLogEntry: a structured audit entry.
+func NewLevelChangeLogEntry(
+	ctx context.Context,
+	target string,
+	previous, next Level.LogLevel,
+	source string,
+	additionalData ...map[string]any,
+) LevelChangeLogEntry {
+	entry := LevelChangeLogEntry{
+		LogEntry:      NewLogEntry(ctx, Level.Warn, "Log level changed", additionalData...),
+		TargetLogger:  target,
+		PreviousLevel: previous,
+		NewLevel:      next,
+		Source:        source,
+	}
+	entry.EventType = "LevelChangeLogEntry"
+	return entry
+}
@@ -20,6 +20,12 @@ type TraceBeginLogEntry struct {
 	Name string `json:"name"`
 }
 
+// Span statuses reported in TraceEndLogEntry.Status.
+const (
+	SpanStatusOK    = "ok"
+	SpanStatusError = "error"
+)
+
 // TraceEndLogEntry represents the end of a trace section previously marked by a TraceBeginLogEntry.
 //
 // It includes the total time spent executing the traced block, measured in milliseconds.
@@ -28,10 +34,15 @@ type TraceBeginLogEntry struct {
 //
 //   - Name: the same name provided in the TraceBeginLogEntry.
 //   - DurationMs: the elapsed time between the TraceBeginLogEntry and this entry.
+//   - Status: the outcome of the traced block, SpanStatusOK or SpanStatusError, when known
+//     (set by chronolog.Span.End).
+//   - Error: the message of the error that ended the traced block, if any.
 type TraceEndLogEntry struct {
 	LogEntry
 	Name       string `json:"name"`
 	DurationMs int64  `json:"duration_ms"`
+	Status     string `json:"status,omitempty"`
+	Error      string `json:"error,omitempty"`
 }
 
 // NewTraceBeginLogEntry creates a new trace entry to mark the start of a measurable block of execution.
@@ -0,0 +1,143 @@
+// Package errors creates errors that record the stack trace of the point where
+// they were created, so that chronolog.Error reports where a failure happened
+// rather than where it was logged.
+//
+// Errors returned by this package implement StackTrace() []uintptr and are fully
+// compatible with the standard errors.Is, errors.As and errors.Unwrap functions.
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+
+	"github.com/Astronotify/chronolog/internal"
+)
+
+// stackError is an error annotated with the program counters captured when it
+// was created.
+type stackError struct {
+	msg   string
+	err   error
+	stack []uintptr
+}
+
+func (e *stackError) Error() string {
+	switch {
+	case e.err == nil:
+		return e.msg
+	case e.msg == "":
+		return e.err.Error()
+	default:
+		return e.msg + ": " + e.err.Error()
+	}
+}
+
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+// StackTrace returns the program counters captured when the error was created.
+func (e *stackError) StackTrace() []uintptr {
+	return e.stack
+}
+
+// Format implements fmt.Formatter. The %+v verb prints the message followed by
+// the stack trace, one frame per line.
+func (e *stackError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		io.WriteString(s, e.Error())
+		if s.Flag('+') {
+			for _, frame := range internal.ResolveFrames(e.stack) {
+				fmt.Fprintf(s, "\n%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+			}
+		}
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+// New returns an error with the given message and the stack trace of the caller.
+//
+// Parameters:
+//
+//   - message (string): the error message.
+//
+// Returns:
+//
+//   - error: an error recording where it was created.
+func New(message string) error {
+	return &stackError{msg: message, stack: internal.Callers(1)}
+}
+
+// Errorf formats an error like fmt.Errorf, including %w wrapping, and records the
+// stack trace of the caller.
+//
+// Parameters:
+//
+//   - format (string): the fmt format string.
+//   - args (...any): the format arguments.
+//
+// Returns:
+//
+//   - error: an error recording where it was created.
+func Errorf(format string, args ...any) error {
+	return &stackError{err: fmt.Errorf(format, args...), stack: internal.Callers(1)}
+}
+
+// Wrap annotates err with a message and the stack trace of the caller.
+//
+// Parameters:
+//
+//   - err (error): the error to wrap. Nil yields nil.
+//   - message (string): the context prepended to err's message as "message: err".
+//
+// Returns:
+//
+//   - error: the wrapped error, or nil if err is nil.
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return &stackError{msg: message, err: err, stack: internal.Callers(1)}
+}
+
+// WithStack records the stack trace of the caller on err without changing its message.
+// It is meant for errors returned by code that does not capture stacks itself.
+//
+// Parameters:
+//
+//   - err (error): the error to annotate. Nil yields nil.
+//
+// Returns:
+//
+//   - error: the annotated error, or nil if err is nil.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &stackError{err: err, stack: internal.Callers(1)}
+}
+
+// Is reports whether any error in err's tree matches target. See errors.Is.
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+// As finds the first error in err's tree that matches target. See errors.As.
+func As(err error, target any) bool {
+	return stderrors.As(err, target)
+}
+
+// Unwrap returns the result of calling the Unwrap method on err. See errors.Unwrap.
+func Unwrap(err error) error {
+	return stderrors.Unwrap(err)
+}
+
+// Join returns an error that wraps the given errors. See errors.Join.
+func Join(errs ...error) error {
+	return stderrors.Join(errs...)
+}
@@ -0,0 +1,97 @@
+package errors_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Astronotify/chronolog/entries"
+	"github.com/Astronotify/chronolog/errors"
+)
+
+func line() int {
+	_, _, n, _ := runtime.Caller(1)
+	return n
+}
+
+func failingOperation() (int, error) {
+	return line(), errors.Wrap(io.EOF, "read header")
+}
+
+func TestStackPointsToCreation(t *testing.T) {
+	created, err := failingOperation()
+
+	entry := entries.NewErrorLogEntry(context.Background(), fmt.Errorf("request failed: %w", err))
+	if len(entry.StackTrace) == 0 {
+		t.Fatal("no stack trace")
+	}
+	top := entry.StackTrace[0]
+	if !strings.HasSuffix(top.Function, ".failingOperation") || top.Line != created {
+		t.Errorf("top frame = %+v, want failingOperation at line %d", top, created)
+	}
+	for _, frame := range entry.StackTrace {
+		if strings.HasPrefix(frame.Function, "github.com/Astronotify/chronolog/errors.") ||
+			strings.HasPrefix(frame.Function, "github.com/Astronotify/chronolog/entries.") {
+			t.Errorf("library frame not trimmed: %+v", frame)
+		}
+	}
+	if entry.ErrorClass != "EOF" {
+		t.Errorf("ErrorClass = %q, want EOF", entry.ErrorClass)
+	}
+}
+
+func TestWrappingKeepsStandardBehavior(t *testing.T) {
+	err := errors.Errorf("load %s: %w", "config", io.ErrUnexpectedEOF)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Error("Errorf lost the wrapped error")
+	}
+	if got := err.Error(); got != "load config: unexpected EOF" {
+		t.Errorf("Error() = %q", got)
+	}
+	if errors.Wrap(nil, "x") != nil || errors.WithStack(nil) != nil {
+		t.Error("wrapping nil must return nil")
+	}
+	if got := fmt.Sprintf("%+v", errors.New("boom")); !strings.Contains(got, "TestWrappingKeepsStandardBehavior") {
+		t.Errorf("%%+v output lacks the stack: %q", got)
+	}
+}
+
+type pkgFrame uintptr
+
+type pkgStyleError struct{ stack []pkgFrame }
+
+func (e *pkgStyleError) Error() string { return "pkg style" }
+
+func (e *pkgStyleError) StackTrace() []pkgFrame { return e.stack }
+
+func TestForeignStackTraceMethod(t *testing.T) {
+	var pcs [8]uintptr
+	n := runtime.Callers(1, pcs[:])
+	stack := make([]pkgFrame, n)
+	for i, pc := range pcs[:n] {
+		stack[i] = pkgFrame(pc)
+	}
+
+	entry := entries.NewErrorLogEntry(context.Background(), &pkgStyleError{stack: stack})
+	if len(entry.StackTrace) == 0 || !strings.HasSuffix(entry.StackTrace[0].Function, ".TestForeignStackTraceMethod") {
+		t.Errorf("stack trace = %+v", entry.StackTrace)
+	}
+}
+
+func TestErrorfCausesAreNotRepeated(t *testing.T) {
+	err := errors.Errorf("charge card: %w", context.DeadlineExceeded)
+
+	causes := entries.NewErrorLogEntry(context.Background(), fmt.Errorf("handle order: %w", err)).Causes
+	if len(causes) != 3 {
+		t.Fatalf("got %d causes, want 3: %+v", len(causes), causes)
+	}
+	if causes[1].Message != "charge card: context deadline exceeded" || causes[1].Type != "*errors.stackError" {
+		t.Errorf("Errorf cause = %+v", causes[1])
+	}
+	if causes[2].Message != "context deadline exceeded" || causes[2].Class != "DeadlineExceeded" {
+		t.Errorf("root cause = %+v", causes[2])
+	}
+}
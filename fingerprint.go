@@ -0,0 +1,56 @@
+package chronolog
+
+import (
+	"regexp"
+
+	"github.com/Astronotify/chronolog/entries"
+	"github.com/Astronotify/chronolog/internal"
+)
+
+// FrameNormalizer rewrites a stack frame before it takes part in an error
+// fingerprint. Returning false leaves the frame out of the fingerprint.
+type FrameNormalizer func(frame entries.StackFrame) (entries.StackFrame, bool)
+
+// AddFingerprintMessageRule masks every match of pattern in error messages with
+// replacement before they are fingerprinted.
+//
+// Numbers, hexadecimal identifiers and UUIDs are always masked; custom rules run
+// afterwards, in registration order. Use them for identifiers specific to the
+// application, such as order references or e-mail addresses.
+//
+// Parameters:
+//
+//   - pattern (*regexp.Regexp): the text to mask. Nil is ignored.
+//   - replacement (string): the placeholder, e.g. "<email>".
+func AddFingerprintMessageRule(pattern *regexp.Regexp, replacement string) {
+	if pattern == nil {
+		return
+	}
+	internal.AddFingerprintMessageRule(internal.MessageRule{Pattern: pattern, Replacement: replacement})
+}
+
+// AddFingerprintFrameNormalizer registers a stack frame normalizer, applied in
+// registration order before frames are fingerprinted. Typical uses are dropping
+// middleware frames or collapsing generated function names.
+//
+// Parameters:
+//
+//   - normalizer (FrameNormalizer): the normalizer to add. Nil is ignored.
+func AddFingerprintFrameNormalizer(normalizer FrameNormalizer) {
+	if normalizer == nil {
+		return
+	}
+	internal.AddFingerprintFrameRule(internal.FrameRule(normalizer))
+}
+
+// SetFingerprintIgnoreLineNumbers selects whether line numbers are left out of
+// error fingerprints. Ignoring them keeps groups stable across deployments that
+// only move code around, at the cost of merging distinct failures raised from the
+// same function. Line numbers are included by default.
+//
+// Parameters:
+//
+//   - ignore (bool): true to fingerprint frames by function and file only.
+func SetFingerprintIgnoreLineNumbers(ignore bool) {
+	internal.SetFingerprintIgnoreLineNumbers(ignore)
+}
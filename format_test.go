@@ -0,0 +1,276 @@
+package chronolog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	chronologctx "github.com/Astronotify/chronolog/ctx"
+	"github.com/Astronotify/chronolog/entries"
+)
+
+func TestLogfmtFormat(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Config{Writer: &buf, Format: FormatLogfmt})
+
+	ctx := chronologctx.WithTraceID(context.Background(), "trace-1")
+	l.Info(ctx, "user signed in", map[string]any{
+		"user":  map[string]any{"id": 42, "name": "Jane Doe"},
+		"quote": `say "hi"`,
+	})
+
+	line := buf.String()
+	if !strings.HasPrefix(line, "timestamp=") {
+		t.Errorf("expected timestamp first, got %q", line)
+	}
+	for _, want := range []string{
+		`level=info event_type=LogEntry message="user signed in"`,
+		`trace_id=trace-1`,
+		`additional_data.user.id=42`,
+		`additional_data.user.name="Jane Doe"`,
+		`additional_data.quote="say \"hi\""`,
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("expected %q in %q", want, line)
+		}
+	}
+	if strings.Count(line, "\n") != 1 {
+		t.Errorf("expected a single line, got %q", line)
+	}
+}
+
+func TestOTLPJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Config{Writer: &buf, Format: FormatOTLPJSON})
+
+	ctx := context.Background()
+	ctx = chronologctx.WithTraceID(ctx, "4bf92f3577b34da6a3ce929d0e0e4736")
+	ctx = chronologctx.WithSpanID(ctx, "00f067aa0ba902b7")
+	ctx = chronologctx.WithVersion(ctx, "1.2.3")
+	l.Warn(ctx, "slow response", map[string]any{"elapsed_ms": 1500})
+
+	var got struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []struct {
+					Key   string
+					Value map[string]any
+				}
+			}
+			ScopeLogs []struct {
+				Scope      struct{ Name string }
+				LogRecords []struct {
+					TimeUnixNano   string
+					SeverityNumber int
+					SeverityText   string
+					TraceID        string `json:"traceId"`
+					SpanID         string `json:"spanId"`
+					Body           map[string]any
+					Attributes     []struct {
+						Key   string
+						Value map[string]any
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid OTLP JSON %q: %v", buf.String(), err)
+	}
+
+	rec := got.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	if rec.SeverityNumber != 13 || rec.SeverityText != "WARN" {
+		t.Errorf("unexpected severity %d %q", rec.SeverityNumber, rec.SeverityText)
+	}
+	if rec.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || rec.SpanID != "00f067aa0ba902b7" {
+		t.Errorf("unexpected ids %q %q", rec.TraceID, rec.SpanID)
+	}
+	if rec.Body["stringValue"] != "slow response" {
+		t.Errorf("unexpected body %v", rec.Body)
+	}
+	if rec.TimeUnixNano == "" {
+		t.Errorf("missing timeUnixNano")
+	}
+	if len(rec.Attributes) != 1 || rec.Attributes[0].Key != "elapsed_ms" || rec.Attributes[0].Value["intValue"] != "1500" {
+		t.Errorf("unexpected attributes %+v", rec.Attributes)
+	}
+	if got.ResourceLogs[0].ScopeLogs[0].Scope.Name != "chronolog" {
+		t.Errorf("unexpected scope %+v", got.ResourceLogs[0].ScopeLogs[0].Scope)
+	}
+	res := got.ResourceLogs[0].Resource.Attributes
+	if len(res) != 1 || res[0].Key != "service.version" || res[0].Value["stringValue"] != "1.2.3" {
+		t.Errorf("unexpected resource attributes %+v", res)
+	}
+}
+
+func TestECSFormat(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Config{Writer: &buf, Format: FormatECS})
+
+	ctx := chronologctx.WithTraceID(context.Background(), "trace-1")
+	req := entries.NewOperationRequestLogEntry(ctx, "CreateUser", "user", "req-1", "/users", "POST")
+	l.Entry(ctx, req)
+	l.Entry(ctx, entries.NewOperationResponseLogEntry(req, 503))
+	l.Error(ctx, errors.New("boom"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 documents, got %d", len(lines))
+	}
+
+	docs := make([]map[string]any, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &docs[i]); err != nil {
+			t.Fatalf("invalid ECS document %q: %v", line, err)
+		}
+	}
+
+	request, response, failure := docs[0], docs[1], docs[2]
+	if request["http.request.method"] != "POST" || request["url.path"] != "/users" || request["http.request.id"] != "req-1" {
+		t.Errorf("unexpected request mapping %v", request)
+	}
+	if request["trace.id"] != "trace-1" || request["log.level"] != "info" || request["@timestamp"] == nil {
+		t.Errorf("unexpected base mapping %v", request)
+	}
+	if response["http.response.status_code"] != float64(503) || response["event.outcome"] != "failure" {
+		t.Errorf("unexpected response mapping %v", response)
+	}
+	if _, ok := response["event.duration"].(float64); !ok {
+		t.Errorf("expected event.duration in nanoseconds, got %v", response["event.duration"])
+	}
+	if failure["error.message"] != "boom" || failure["error.type"] == nil {
+		t.Errorf("unexpected error mapping %v", failure)
+	}
+}
+
+func TestGCPFormat(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Config{Writer: &buf, Format: FormatGCP, GCPProjectID: "my-project"})
+
+	ctx := chronologctx.WithTraceID(context.Background(), "abc123")
+	ctx = chronologctx.WithSpanID(ctx, "span-1")
+	req := entries.NewOperationRequestLogEntry(ctx, "GetUser", "user", "req-1", "/users/1", "GET")
+	res := entries.NewOperationResponseLogEntry(req, 404)
+	res.DurationMs = 1500
+	l.Entry(ctx, res)
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+
+	if got["severity"] != "INFO" {
+		t.Errorf("unexpected severity %v", got["severity"])
+	}
+	if got["logging.googleapis.com/trace"] != "projects/my-project/traces/abc123" {
+		t.Errorf("unexpected trace %v", got["logging.googleapis.com/trace"])
+	}
+	if got["logging.googleapis.com/spanId"] != "span-1" {
+		t.Errorf("unexpected span %v", got["logging.googleapis.com/spanId"])
+	}
+	httpRequest, _ := got["httpRequest"].(map[string]any)
+	if httpRequest["status"] != float64(404) || httpRequest["latency"] != "1.5s" {
+		t.Errorf("unexpected httpRequest %v", got["httpRequest"])
+	}
+	if _, ok := got["logging.googleapis.com/sourceLocation"].(map[string]any); !ok {
+		t.Errorf("expected sourceLocation, got %v", got)
+	}
+	if _, ok := got["level"]; ok {
+		t.Errorf("level should be replaced by severity")
+	}
+}
+
+func TestEMFFormat(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Config{Writer: &buf, Format: FormatEMF, EMF: &EMFConfig{Namespace: "Payments"}})
+
+	ctx := context.Background()
+	begin := entries.NewLambdaBeginLogEntry(ctx, "Charge", "req-1")
+	l.Entry(ctx, begin)
+	l.Entry(ctx, entries.NewLambdaEndLogEntryFromBegin(begin))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+
+	var plain map[string]any
+	json.Unmarshal([]byte(lines[0]), &plain)
+	if _, ok := plain["_aws"]; ok {
+		t.Errorf("begin entry should not declare metrics: %v", plain)
+	}
+
+	var metric struct {
+		AWS struct {
+			Timestamp         int64
+			CloudWatchMetrics []struct {
+				Namespace  string
+				Dimensions [][]string
+				Metrics    []struct{ Name, Unit string }
+			}
+		} `json:"_aws"`
+		FunctionName string
+		DurationMs   *float64
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &metric); err != nil {
+		t.Fatalf("invalid EMF line %q: %v", lines[1], err)
+	}
+	if len(metric.AWS.CloudWatchMetrics) != 1 {
+		t.Fatalf("expected one metric directive, got %q", lines[1])
+	}
+	directive := metric.AWS.CloudWatchMetrics[0]
+	if directive.Namespace != "Payments" || directive.Dimensions[0][0] != "FunctionName" {
+		t.Errorf("unexpected directive %+v", directive)
+	}
+	if directive.Metrics[0].Name != "DurationMs" || directive.Metrics[0].Unit != "Milliseconds" {
+		t.Errorf("unexpected metric %+v", directive.Metrics)
+	}
+	if metric.FunctionName != "Charge" || metric.DurationMs == nil || metric.AWS.Timestamp == 0 {
+		t.Errorf("missing dimension or metric values in %q", lines[1])
+	}
+}
+
+func TestPrettyFormatTraceSampled(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Config{Writer: &buf, Format: FormatPretty})
+
+	l.Info(context.Background(), "no decision")
+	l.Info(chronologctx.WithTraceSampled(context.Background(), true), "sampled")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	if strings.Contains(lines[0], "trace_sampled") {
+		t.Errorf("unset flag rendered: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "trace_sampled=true") {
+		t.Errorf("flag not rendered as a boolean: %q", lines[1])
+	}
+}
+
+func TestPrettyFormatBaggage(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Config{Writer: &buf, Format: FormatPretty, BaggageKeys: []string{"tenant", "region"}})
+
+	ctx, err := chronologctx.FromBaggage(context.Background(), "tenant=acme,region=eu-west-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info(ctx, "with baggage")
+	l.Info(context.Background(), "without")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], "baggage=map[region=eu-west-1 tenant=acme]") {
+		t.Errorf("baggage not rendered as sorted pairs: %q", lines[0])
+	}
+	if strings.Contains(lines[1], "baggage") {
+		t.Errorf("empty baggage rendered: %q", lines[1])
+	}
+}
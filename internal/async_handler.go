@@ -0,0 +1,196 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrHandlerClosed is returned by AsyncHandler.Handle once the handler has been closed.
+var ErrHandlerClosed = errors.New("chronolog: handler closed")
+
+// OverflowPolicy decides what AsyncHandler does with a record when its queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes the caller wait until the queue has room.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest discards the incoming record.
+	OverflowDropNewest
+	// OverflowDropOldest evicts the oldest queued record to make room for the incoming one.
+	OverflowDropOldest
+	// OverflowDropBelowLevel discards incoming records below a threshold and blocks for the others.
+	OverflowDropBelowLevel
+)
+
+type asyncItem struct {
+	ctx    context.Context
+	record slog.Record
+}
+
+// AsyncHandler is a slog.Handler that enqueues records into a bounded queue and
+// forwards them to the wrapped handler from a background goroutine.
+type AsyncHandler struct {
+	next      slog.Handler
+	policy    OverflowPolicy
+	dropBelow slog.Level
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	progress *sync.Cond
+	queue    []asyncItem
+	head     int
+	count    int
+	enqueued uint64
+	settled  uint64
+	closed   bool
+
+	dropped atomic.Uint64
+	done    chan struct{}
+}
+
+// NewAsyncHandler creates an AsyncHandler with the given queue size and starts its worker.
+// Records with a level below dropBelow are the ones discarded by OverflowDropBelowLevel.
+func NewAsyncHandler(next slog.Handler, queueSize int, policy OverflowPolicy, dropBelow slog.Level) *AsyncHandler {
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	h := &AsyncHandler{
+		next:      next,
+		policy:    policy,
+		dropBelow: dropBelow,
+		queue:     make([]asyncItem, queueSize),
+		done:      make(chan struct{}),
+	}
+	h.notEmpty = sync.NewCond(&h.mu)
+	h.notFull = sync.NewCond(&h.mu)
+	h.progress = sync.NewCond(&h.mu)
+
+	go h.run()
+	return h
+}
+
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *AsyncHandler) Handle(ctx context.Context, record slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for h.count == len(h.queue) && !h.closed {
+		switch h.policy {
+		case OverflowDropNewest:
+			h.dropped.Add(1)
+			return nil
+		case OverflowDropOldest:
+			h.queue[h.head] = asyncItem{}
+			h.head = (h.head + 1) % len(h.queue)
+			h.count--
+			h.settled++
+			h.dropped.Add(1)
+			h.progress.Broadcast()
+		case OverflowDropBelowLevel:
+			if record.Level < h.dropBelow {
+				h.dropped.Add(1)
+				return nil
+			}
+			h.notFull.Wait()
+		default:
+			h.notFull.Wait()
+		}
+	}
+
+	if h.closed {
+		h.dropped.Add(1)
+		return ErrHandlerClosed
+	}
+
+	tail := (h.head + h.count) % len(h.queue)
+	h.queue[tail] = asyncItem{ctx: context.WithoutCancel(ctx), record: record.Clone()}
+	h.count++
+	h.enqueued++
+	h.notEmpty.Signal()
+	return nil
+}
+
+func (h *AsyncHandler) WithAttrs(_ []slog.Attr) slog.Handler {
+	// no-op, stateless handler
+	return h
+}
+
+func (h *AsyncHandler) WithGroup(_ string) slog.Handler {
+	// no-op, stateless handler
+	return h
+}
+
+// Dropped returns the number of records discarded because of the overflow policy
+// or because they arrived after Close.
+func (h *AsyncHandler) Dropped() uint64 {
+	return h.dropped.Load()
+}
+
+// Flush blocks until every record enqueued before the call has been handed to
+// the wrapped handler (or evicted), or until ctx is done.
+func (h *AsyncHandler) Flush(ctx context.Context) error {
+	stop := context.AfterFunc(ctx, func() {
+		h.mu.Lock()
+		h.progress.Broadcast()
+		h.mu.Unlock()
+	})
+	defer stop()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	target := h.enqueued
+	for h.settled < target {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		h.progress.Wait()
+	}
+	return nil
+}
+
+// Close stops accepting records, drains the queue and waits for the worker to exit.
+func (h *AsyncHandler) Close() {
+	h.mu.Lock()
+	h.closed = true
+	h.notEmpty.Broadcast()
+	h.notFull.Broadcast()
+	h.mu.Unlock()
+
+	<-h.done
+}
+
+func (h *AsyncHandler) run() {
+	defer close(h.done)
+
+	for {
+		h.mu.Lock()
+		for h.count == 0 && !h.closed {
+			h.notEmpty.Wait()
+		}
+		if h.count == 0 {
+			h.mu.Unlock()
+			return
+		}
+		item := h.queue[h.head]
+		h.queue[h.head] = asyncItem{}
+		h.head = (h.head + 1) % len(h.queue)
+		h.count--
+		h.notFull.Signal()
+		h.mu.Unlock()
+
+		_ = h.next.Handle(item.ctx, item.record)
+
+		h.mu.Lock()
+		h.settled++
+		h.progress.Broadcast()
+		h.mu.Unlock()
+	}
+}
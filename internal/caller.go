@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"runtime"
+	"strings"
+)
+
+// modulePath is the import path prefix of every chronolog package.
+const modulePath = "github.com/Astronotify/chronolog"
+
+// libraryPackages are the chronolog packages whose frames are hidden from callers
+// and stack traces.
+var libraryPackages = map[string]bool{
+	modulePath:               true,
+	modulePath + "/ctx":      true,
+	modulePath + "/entries":  true,
+	modulePath + "/errors":   true,
+	modulePath + "/internal": true,
+}
+
+// CallerPC returns the program counter of the first frame outside chronolog,
+// skipping skip additional frames above the caller of CallerPC.
+// It returns 0 if no such frame exists.
+func CallerPC(skip int) uintptr {
+	var pcs [32]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	for _, pc := range pcs[:n] {
+		fn := runtime.FuncForPC(pc - 1)
+		if fn == nil || !IsLibraryFunction(fn.Name()) {
+			return pc
+		}
+	}
+	return 0
+}
+
+// IsLibraryFunction reports whether a fully qualified function name, as returned
+// by runtime.Frame.Function, belongs to a chronolog package.
+func IsLibraryFunction(function string) bool {
+	if !strings.HasPrefix(function, modulePath) {
+		return false
+	}
+	pkg := function
+	slash := strings.LastIndex(pkg, "/")
+	if dot := strings.Index(pkg[slash+1:], "."); dot >= 0 {
+		pkg = pkg[:slash+1+dot]
+	}
+	return libraryPackages[pkg]
+}
@@ -0,0 +1,64 @@
+package internal
+
+import "errors"
+
+// ErrorCause describes one error of a causal chain.
+//
+// Branches is set on multi-errors (errors.Join, fmt.Errorf with several %w, or any
+// Unwrap() []error implementation) and holds the chain of each wrapped error.
+type ErrorCause struct {
+	Type     string         `json:"type"`
+	Message  string         `json:"message"`
+	Class    string         `json:"class,omitempty"`
+	Branches [][]ErrorCause `json:"branches,omitempty"`
+}
+
+// ExtractCauses lists err and every error it wraps, outermost first.
+//
+// Single-error chains are flattened; a multi-error ends its chain and lists the
+// chains of its wrapped errors as branches. A cause whose message is exactly the
+// one of its wrapper, such as the fmt.Errorf error inside errors.Errorf, is
+// dropped rather than repeated. At most maxUnwrapDepth causes are reported in
+// total.
+func ExtractCauses(err error) []ErrorCause {
+	budget := maxUnwrapDepth
+	return extractCauses(err, &budget)
+}
+
+func extractCauses(err error, budget *int) []ErrorCause {
+	var causes []ErrorCause
+	for err != nil && *budget > 0 {
+		*budget--
+		cause := ErrorCause{
+			Type:    ErrorTypeName(err),
+			Message: err.Error(),
+			Class:   ClassifyError(err),
+		}
+
+		// the wrapper already shows the whole message: keep it in place of the cause
+		var wrapper *ErrorCause
+		if n := len(causes); n > 0 && causes[n-1].Message == cause.Message {
+			wrapper = &causes[n-1]
+		}
+
+		if multi, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, branch := range multi.Unwrap() {
+				if branch == nil || *budget <= 0 {
+					continue
+				}
+				cause.Branches = append(cause.Branches, extractCauses(branch, budget))
+			}
+			if wrapper != nil {
+				wrapper.Branches = cause.Branches
+				return causes
+			}
+			return append(causes, cause)
+		}
+
+		if wrapper == nil {
+			causes = append(causes, cause)
+		}
+		err = errors.Unwrap(err)
+	}
+	return causes
+}
@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestExtractCausesChain(t *testing.T) {
+	err := fmt.Errorf("handle order: %w", fmt.Errorf("charge card: %w", context.DeadlineExceeded))
+
+	causes := ExtractCauses(err)
+	if len(causes) != 3 {
+		t.Fatalf("got %d causes, want 3: %+v", len(causes), causes)
+	}
+	if causes[0].Message != err.Error() || causes[0].Type != "*fmt.wrapError" {
+		t.Errorf("outermost cause = %+v", causes[0])
+	}
+	if causes[2].Type != "context.deadlineExceededError" || causes[2].Class != "DeadlineExceeded" {
+		t.Errorf("root cause = %+v", causes[2])
+	}
+}
+
+func TestExtractCausesPassThroughWrapper(t *testing.T) {
+	err := fmt.Errorf("%w", errors.Join(io.EOF, context.Canceled))
+
+	causes := ExtractCauses(err)
+	if len(causes) != 1 || causes[0].Type != "*fmt.wrapError" || len(causes[0].Branches) != 2 {
+		t.Fatalf("causes = %+v, want the wrapper carrying both branches", causes)
+	}
+}
+
+func TestExtractCausesJoin(t *testing.T) {
+	err := fmt.Errorf("fan-out: %w", errors.Join(
+		io.EOF,
+		fmt.Errorf("shard 2: %w", context.Canceled),
+		&validationError{field: "id"},
+	))
+
+	causes := ExtractCauses(err)
+	if len(causes) != 2 {
+		t.Fatalf("got %d causes, want 2: %+v", len(causes), causes)
+	}
+	branches := causes[1].Branches
+	if causes[1].Type != "*errors.joinError" || len(branches) != 3 {
+		t.Fatalf("join cause = %+v", causes[1])
+	}
+	if len(branches[1]) != 2 || branches[1][1].Class != "Canceled" {
+		t.Errorf("second branch = %+v", branches[1])
+	}
+	if branches[2][0].Message != "invalid id" {
+		t.Errorf("third branch = %+v", branches[2])
+	}
+}
@@ -0,0 +1,138 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"sync"
+)
+
+// GenericErrorClass is reported when nothing more specific is known about an error.
+const GenericErrorClass = "GenericError"
+
+// ErrorClassifier inspects an error and returns its class, or false when it does
+// not recognize it.
+type ErrorClassifier func(err error) (string, bool)
+
+var (
+	classifiersMu sync.RWMutex
+	classifiers   []ErrorClassifier
+)
+
+// genericErrorTypes are standard library types that only carry a message, so
+// their type name says nothing about the failure.
+var genericErrorTypes = map[string]bool{
+	"*errors.errorString": true,
+	"*errors.joinError":   true,
+	"*fmt.wrapError":      true,
+	"*fmt.wrapErrors":     true,
+	"*errors.stackError":  true, // chronolog/errors
+}
+
+// RegisterErrorClassifier adds a classifier consulted by ClassifyError, after
+// errors implementing ChronologClass and before the built-in rules.
+func RegisterErrorClassifier(classifier ErrorClassifier) {
+	if classifier == nil {
+		return
+	}
+	classifiersMu.Lock()
+	defer classifiersMu.Unlock()
+	classifiers = append(classifiers, classifier)
+}
+
+// ClassifyError returns a short class used to group errors.
+//
+// The class is resolved in order from:
+//   - the first error in the chain implementing ChronologClass() string;
+//   - the registered classifiers, in registration order;
+//   - well-known sentinels and types (context cancellation, io.EOF, os.ErrNotExist,
+//     network timeouts, *url.Error, *os.PathError, ...);
+//   - the concrete type name of the root error, unless it is a plain message error.
+func ClassifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var classed interface{ ChronologClass() string }
+	if errors.As(err, &classed) {
+		if class := classed.ChronologClass(); class != "" {
+			return class
+		}
+	}
+
+	classifiersMu.RLock()
+	registered := classifiers
+	classifiersMu.RUnlock()
+	for _, classify := range registered {
+		if class, ok := classify(err); ok && class != "" {
+			return class
+		}
+	}
+
+	if class, ok := classifyWellKnown(err); ok {
+		return class
+	}
+
+	if name := ErrorTypeName(RootError(err)); !genericErrorTypes[name] {
+		return name
+	}
+	return GenericErrorClass
+}
+
+func classifyWellKnown(err error) (string, bool) {
+	var netErr net.Error
+	var urlErr *url.Error
+	var pathErr *os.PathError
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "Canceled", true
+	case errors.Is(err, context.DeadlineExceeded):
+		return "DeadlineExceeded", true
+	case errors.Is(err, io.EOF):
+		return "EOF", true
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return "UnexpectedEOF", true
+	case errors.Is(err, os.ErrNotExist):
+		return "NotExist", true
+	case errors.Is(err, os.ErrPermission):
+		return "PermissionDenied", true
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "Timeout", true
+	case errors.As(err, &urlErr):
+		return "URLError", true
+	case errors.As(err, &pathErr):
+		return "PathError", true
+	case netErr != nil:
+		return "NetworkError", true
+	}
+	return "", false
+}
+
+// RootError follows the errors.Unwrap chain and returns the innermost error.
+// Multi-errors (Unwrap() []error) are considered roots.
+func RootError(err error) error {
+	for depth := 0; err != nil && depth < maxUnwrapDepth; depth++ {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+	return err
+}
+
+// ErrorTypeName returns the concrete Go type of err, such as "*fs.PathError".
+func ErrorTypeName(err error) string {
+	if err == nil {
+		return ""
+	}
+	return reflect.TypeOf(err).String()
+}
+
+// maxUnwrapDepth protects against cyclic Unwrap implementations.
+const maxUnwrapDepth = 64
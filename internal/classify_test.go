@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+type classedError struct{}
+
+func (classedError) Error() string          { return "classed" }
+func (classedError) ChronologClass() string { return "PaymentDeclined" }
+
+type validationError struct{ field string }
+
+func (e *validationError) Error() string { return "invalid " + e.field }
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassifyError(t *testing.T) {
+	_, pathErr := os.Open("/does/not/exist")
+	_, urlErr := http.Get("://bad")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	cases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"plain", errors.New("boom"), GenericErrorClass},
+		{"wrapped plain", fmt.Errorf("ctx: %w", errors.New("boom")), GenericErrorClass},
+		{"interface", fmt.Errorf("wrap: %w", classedError{}), "PaymentDeclined"},
+		{"canceled", fmt.Errorf("wrap: %w", context.Canceled), "Canceled"},
+		{"deadline", ctx.Err(), "DeadlineExceeded"},
+		{"eof", fmt.Errorf("read: %w", io.EOF), "EOF"},
+		{"not exist", pathErr, "NotExist"},
+		{"timeout", fmt.Errorf("dial: %w", timeoutError{}), "Timeout"},
+		{"url", urlErr, "URLError"},
+		{"path", &os.PathError{Op: "open", Path: "x", Err: errors.New("busy")}, "PathError"},
+		{"root type", fmt.Errorf("save: %w", &validationError{field: "email"}), "*internal.validationError"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ClassifyError(tc.err); got != tc.want {
+				t.Errorf("ClassifyError() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRegisteredClassifier(t *testing.T) {
+	defer func(saved []ErrorClassifier) { classifiers = saved }(classifiers)
+
+	RegisterErrorClassifier(func(err error) (string, bool) {
+		var v *validationError
+		if errors.As(err, &v) {
+			return "ValidationError", true
+		}
+		return "", false
+	})
+
+	err := fmt.Errorf("save: %w", &validationError{field: "email"})
+	if got := ClassifyError(err); got != "ValidationError" {
+		t.Errorf("ClassifyError() = %q, want ValidationError", got)
+	}
+	// the interface still takes precedence over classifiers
+	if got := ClassifyError(classedError{}); got != "PaymentDeclined" {
+		t.Errorf("ClassifyError() = %q, want PaymentDeclined", got)
+	}
+}
+
+func TestErrorTypeNames(t *testing.T) {
+	_, err := os.Open("/does/not/exist")
+	wrapped := fmt.Errorf("load config: %w", err)
+
+	if got := ErrorTypeName(wrapped); got != "*fmt.wrapError" {
+		t.Errorf("ErrorTypeName() = %q", got)
+	}
+	if got := ErrorTypeName(RootError(wrapped)); got != "syscall.Errno" {
+		t.Errorf("root ErrorTypeName() = %q", got)
+	}
+}
@@ -11,8 +11,20 @@ const (
 	CommitHashKey   contextKey = "commit_hash"
 	BuildTimeKey    contextKey = "build_time"
 	VersionKey      contextKey = "version"
+	TraceSampledKey contextKey = "trace_sampled"
+	TraceStateKey   contextKey = "trace_state"
+	FieldsKey       contextKey = "fields"
+	BaggageKey      contextKey = "baggage"
 )
 
+// BaggageMember is one list-member of W3C baggage, with its value percent-decoded
+// and its properties kept verbatim (e.g. "ttl=60").
+type BaggageMember struct {
+	Key        string
+	Value      string
+	Properties string
+}
+
 func ExtractTraceID(ctx context.Context) string {
 	v := ctx.Value(TraceIDKey)
 	if v != nil {
@@ -61,6 +73,33 @@ func ExtractVersion(ctx context.Context) string {
 	return ""
 }
 
+// ExtractTraceSampled returns the sampled flag of the trace, and false as second
+// value when no sampling decision was propagated.
+func ExtractTraceSampled(ctx context.Context) (bool, bool) {
+	v := ctx.Value(TraceSampledKey)
+	if v != nil {
+		return v.(bool), true
+	}
+	return false, false
+}
+
+func ExtractTraceState(ctx context.Context) string {
+	v := ctx.Value(TraceStateKey)
+	if v != nil {
+		return v.(string)
+	}
+	return ""
+}
+
+// ExtractBaggage returns the baggage members of ctx, in order. The slice must not be modified.
+func ExtractBaggage(ctx context.Context) []BaggageMember {
+	v := ctx.Value(BaggageKey)
+	if v != nil {
+		return v.([]BaggageMember)
+	}
+	return nil
+}
+
 func WithTraceID(ctx context.Context, traceID string) context.Context {
 	return context.WithValue(ctx, TraceIDKey, traceID)
 }
@@ -84,3 +123,21 @@ func WithBuildTime(ctx context.Context, buildTime string) context.Context {
 func WithVersion(ctx context.Context, version string) context.Context {
 	return context.WithValue(ctx, VersionKey, version)
 }
+
+func WithTraceSampled(ctx context.Context, sampled bool) context.Context {
+	return context.WithValue(ctx, TraceSampledKey, sampled)
+}
+
+// WithoutTraceSampled hides any sampling decision stored in ctx, so that
+// ExtractTraceSampled reports none.
+func WithoutTraceSampled(ctx context.Context) context.Context {
+	return context.WithValue(ctx, TraceSampledKey, nil)
+}
+
+func WithTraceState(ctx context.Context, traceState string) context.Context {
+	return context.WithValue(ctx, TraceStateKey, traceState)
+}
+
+func WithBaggage(ctx context.Context, members []BaggageMember) context.Context {
+	return context.WithValue(ctx, BaggageKey, members)
+}
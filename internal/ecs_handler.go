@@ -0,0 +1,164 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strconv"
+)
+
+// ecsVersion is the Elastic Common Schema version the ECS handler targets.
+const ecsVersion = "8.11.0"
+
+// ecsCommonFields maps entry fields shared by every entry type onto ECS fields.
+var ecsCommonFields = map[string]string{
+	"timestamp":          "@timestamp",
+	"level":              "log.level",
+	"message":            "message",
+	"event_type":         "event.action",
+	"logger":             "log.logger",
+	"trace_id":           "trace.id",
+	"span_id":            "span.id",
+	"version":            "service.version",
+	"http_method":        "http.request.method",
+	"path":               "url.path",
+	"http_status":        "http.response.status_code",
+	"error_class":        "error.type",
+	"error_message":      "error.message",
+	"cluster_name":       "orchestrator.cluster.name",
+	"namespace":          "orchestrator.namespace",
+	"pod_name":           "orchestrator.resource.name",
+	"container":          "container.name",
+	"node_name":          "host.name",
+	"function_name":      "faas.name",
+	"message_id":         "event.id",
+	"reason":             "event.reason",
+	"library_name":       "chronolog.library.name",
+	"library_version":    "chronolog.library.version",
+	"library_commit":     "chronolog.library.commit",
+	"library_build_time": "chronolog.library.build_time",
+}
+
+// ecsRequestIDFields maps request_id according to the entry type that carries it.
+var ecsRequestIDFields = map[string]string{
+	"OperationRequestLogEntry":  "http.request.id",
+	"OperationResponseLogEntry": "http.request.id",
+	"LambdaBeginLogEntry":       "faas.execution",
+	"LambdaEndLogEntry":         "faas.execution",
+}
+
+// ECSHandler is a slog.Handler that prints the "event" field as an Elastic Common
+// Schema document, mapping each entry type's fields onto their ECS counterparts.
+//
+// Fields without an ECS equivalent are kept under the "chronolog." namespace.
+type ECSHandler struct {
+	writer io.Writer
+}
+
+// NewECSHandler creates a new ECSHandler.
+func NewECSHandler(w io.Writer) *ECSHandler {
+	return &ECSHandler{writer: w}
+}
+
+func (h *ECSHandler) Enabled(_ context.Context, _ slog.Level) bool {
+	return true
+}
+
+func (h *ECSHandler) Handle(_ context.Context, record slog.Record) error {
+	event := ExtractEvent(record)
+	if event == nil {
+		return nil // nothing to log
+	}
+
+	fields, err := EventFields(event)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(h.writer)
+	return enc.Encode(ecsDocument(fields))
+}
+
+func (h *ECSHandler) WithAttrs(_ []slog.Attr) slog.Handler {
+	// no-op, stateless handler
+	return h
+}
+
+func (h *ECSHandler) WithGroup(_ string) slog.Handler {
+	// no-op, stateless handler
+	return h
+}
+
+// ecsDocument converts the JSON fields of an entry into a flat ECS document with dotted keys.
+func ecsDocument(fields map[string]any) map[string]any {
+	eventType, _ := fields["event_type"].(string)
+
+	doc := map[string]any{
+		"ecs.version": ecsVersion,
+	}
+	for key, value := range fields {
+		switch key {
+		case "duration_ms":
+			if n, ok := value.(json.Number); ok {
+				if ms, err := n.Int64(); err == nil {
+					doc["event.duration"] = ms * 1_000_000
+				}
+			}
+		case "request_id":
+			if name, ok := ecsRequestIDFields[eventType]; ok {
+				doc[name] = value
+			} else {
+				doc["chronolog.request_id"] = value
+			}
+		case "stack_trace":
+			doc["error.stack_trace"] = FormatStackTrace(value)
+		case "additional_data":
+			if data, ok := value.(map[string]any); ok && len(data) > 0 {
+				doc["chronolog.additional_data"] = data
+			}
+		default:
+			if name, ok := ecsCommonFields[key]; ok {
+				doc[name] = value
+			} else {
+				doc["chronolog."+key] = value
+			}
+		}
+	}
+
+	switch eventType {
+	case "K8SLogEntry":
+		doc["orchestrator.type"] = "kubernetes"
+		doc["orchestrator.resource.type"] = "pod"
+	case "OperationResponseLogEntry":
+		doc["event.outcome"] = ecsHTTPOutcome(fields["http_status"])
+	case "MessageAcknowledgedLogEntry", "LambdaEndLogEntry":
+		doc["event.outcome"] = "success"
+	case "MessageRejectedLogEntry", "ErrorLogEntry":
+		doc["event.outcome"] = "failure"
+	case "TraceEndLogEntry":
+		switch fields["status"] {
+		case "ok":
+			doc["event.outcome"] = "success"
+		case "error":
+			doc["event.outcome"] = "failure"
+		}
+	}
+
+	return doc
+}
+
+func ecsHTTPOutcome(status any) string {
+	n, ok := status.(json.Number)
+	if !ok {
+		return "unknown"
+	}
+	code, err := strconv.Atoi(n.String())
+	if err != nil || code == 0 {
+		return "unknown"
+	}
+	if code >= 400 {
+		return "failure"
+	}
+	return "success"
+}
@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// EMFDimension declares a CloudWatch dimension whose value is read from an entry field.
+type EMFDimension struct {
+	Name  string
+	Field string
+}
+
+// EMFRule describes the metric emitted for one entry type.
+type EMFRule struct {
+	Dimensions []EMFDimension
+}
+
+// EMFHandler is a slog.Handler that prints the "event" field as JSON enriched with
+// the CloudWatch Embedded Metric Format metadata, so that duration-bearing entries
+// become DurationMs metrics without metric filters.
+//
+// Entries whose event type has no rule are printed as plain JSON.
+type EMFHandler struct {
+	writer    io.Writer
+	namespace string
+	rules     map[string]EMFRule
+}
+
+// NewEMFHandler creates a new EMFHandler publishing metrics under namespace,
+// with one rule per event type.
+func NewEMFHandler(w io.Writer, namespace string, rules map[string]EMFRule) *EMFHandler {
+	return &EMFHandler{
+		writer:    w,
+		namespace: namespace,
+		rules:     rules,
+	}
+}
+
+func (h *EMFHandler) Enabled(_ context.Context, _ slog.Level) bool {
+	return true
+}
+
+func (h *EMFHandler) Handle(_ context.Context, record slog.Record) error {
+	event := ExtractEvent(record)
+	if event == nil {
+		return nil // nothing to log
+	}
+
+	fields, err := EventFields(event)
+	if err != nil {
+		return err
+	}
+
+	eventType, _ := fields["event_type"].(string)
+	duration, hasDuration := fields["duration_ms"].(json.Number)
+	rule, hasRule := h.rules[eventType]
+	if hasRule && hasDuration {
+		h.addMetric(fields, rule, duration, record.Time)
+	}
+
+	enc := json.NewEncoder(h.writer)
+	return enc.Encode(fields)
+}
+
+func (h *EMFHandler) WithAttrs(_ []slog.Attr) slog.Handler {
+	// no-op, stateless handler
+	return h
+}
+
+func (h *EMFHandler) WithGroup(_ string) slog.Handler {
+	// no-op, stateless handler
+	return h
+}
+
+// addMetric adds the dimension values, the DurationMs member and the _aws block to fields.
+func (h *EMFHandler) addMetric(fields map[string]any, rule EMFRule, duration json.Number, at time.Time) {
+	dimensions := make([]string, 0, len(rule.Dimensions))
+	for _, d := range rule.Dimensions {
+		value, ok := fields[d.Field]
+		if !ok || value == nil {
+			continue
+		}
+		// EMF dimension values must be strings
+		fields[d.Name] = fmt.Sprint(value)
+		dimensions = append(dimensions, d.Name)
+	}
+
+	fields["DurationMs"] = duration
+	fields["_aws"] = map[string]any{
+		"Timestamp": at.UnixMilli(),
+		"CloudWatchMetrics": []map[string]any{{
+			"Namespace":  h.namespace,
+			"Dimensions": [][]string{dimensions},
+			"Metrics": []map[string]string{{
+				"Name": "DurationMs",
+				"Unit": "Milliseconds",
+			}},
+		}},
+	}
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// contextFields holds the typed context keys registered with RegisterContextField,
+// in registration order.
+var contextFields struct {
+	sync.RWMutex
+	names   []string
+	extract map[string]func(context.Context) (any, bool)
+}
+
+// WithFields returns a context carrying fields merged over those already in ctx.
+// Keys set by inner contexts win; the maps of outer contexts are not modified.
+func WithFields(ctx context.Context, fields map[string]any) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	parent, _ := ctx.Value(FieldsKey).(map[string]any)
+	return context.WithValue(ctx, FieldsKey, MergeAdditionalData(parent, fields))
+}
+
+// RegisterContextField registers a typed context key whose value, when extract
+// reports one, is lifted into the context fields of every entry under name.
+// It panics if name is empty or already registered.
+func RegisterContextField(name string, extract func(context.Context) (any, bool)) {
+	if name == "" {
+		panic("chronolog: empty context field name")
+	}
+
+	contextFields.Lock()
+	defer contextFields.Unlock()
+
+	if _, exists := contextFields.extract[name]; exists {
+		panic(fmt.Sprintf("chronolog: context field %q already registered", name))
+	}
+	if contextFields.extract == nil {
+		contextFields.extract = make(map[string]func(context.Context) (any, bool))
+	}
+	contextFields.names = append(contextFields.names, name)
+	contextFields.extract[name] = extract
+}
+
+// ExtractContextFields returns a new map with the fields set with WithFields and
+// the values of registered context keys found in ctx, which take precedence.
+// It returns nil when there are none.
+func ExtractContextFields(ctx context.Context) map[string]any {
+	var out map[string]any
+	if fields, ok := ctx.Value(FieldsKey).(map[string]any); ok {
+		out = MergeAdditionalData(fields)
+	}
+
+	contextFields.RLock()
+	defer contextFields.RUnlock()
+
+	for _, name := range contextFields.names {
+		value, ok := contextFields.extract[name](ctx)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]any)
+		}
+		out[name] = value
+	}
+	return out
+}
@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MessageRule replaces every match of Pattern in an error message with Replacement
+// before the message takes part in a fingerprint.
+type MessageRule struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// FrameRule normalizes a stack frame before it takes part in a fingerprint.
+// Returning false drops the frame.
+type FrameRule func(frame StackFrame) (StackFrame, bool)
+
+// maxFingerprintFrames bounds the number of frames hashed into a fingerprint, so
+// that differences deep in the call stack do not split a group.
+const maxFingerprintFrames = 10
+
+// defaultMessageRules mask the variable parts of common error messages. Order
+// matters: UUIDs are masked before their hex and numeric fragments.
+var defaultMessageRules = []MessageRule{
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`(?i)\b(?:0x[0-9a-f]+|[0-9a-f]{8,})\b`), "<hex>"},
+	{regexp.MustCompile(`\d+(?:\.\d+)?`), "<n>"},
+}
+
+var (
+	fingerprintMu     sync.RWMutex
+	messageRules      []MessageRule
+	frameRules        []FrameRule
+	ignoreLineNumbers bool
+)
+
+// AddFingerprintMessageRule appends a message rule, applied after the defaults.
+func AddFingerprintMessageRule(rule MessageRule) {
+	fingerprintMu.Lock()
+	defer fingerprintMu.Unlock()
+	messageRules = append(messageRules, rule)
+}
+
+// AddFingerprintFrameRule appends a frame rule, applied in registration order.
+func AddFingerprintFrameRule(rule FrameRule) {
+	fingerprintMu.Lock()
+	defer fingerprintMu.Unlock()
+	frameRules = append(frameRules, rule)
+}
+
+// SetFingerprintIgnoreLineNumbers selects whether line numbers are left out of
+// fingerprints, so that unrelated edits to a file do not change them.
+func SetFingerprintIgnoreLineNumbers(ignore bool) {
+	fingerprintMu.Lock()
+	defer fingerprintMu.Unlock()
+	ignoreLineNumbers = ignore
+}
+
+// MessageTemplate masks numbers, hexadecimal identifiers, UUIDs and anything
+// matched by registered rules in message.
+func MessageTemplate(message string) string {
+	fingerprintMu.RLock()
+	custom := messageRules
+	fingerprintMu.RUnlock()
+
+	for _, rules := range [][]MessageRule{defaultMessageRules, custom} {
+		for _, rule := range rules {
+			message = rule.Pattern.ReplaceAllString(message, rule.Replacement)
+		}
+	}
+	return message
+}
+
+// Fingerprint returns a stable identifier for an error, computed from its class,
+// its message template and its normalized stack frames.
+//
+// Frames are reduced to their function, file base name and, unless line numbers
+// are ignored, line; the file directory depends on the build machine.
+func Fingerprint(class, message string, frames []StackFrame) string {
+	fingerprintMu.RLock()
+	rules := frameRules
+	ignoreLines := ignoreLineNumbers
+	fingerprintMu.RUnlock()
+
+	h := sha256.New()
+	h.Write([]byte(class))
+	h.Write([]byte{0})
+	h.Write([]byte(MessageTemplate(message)))
+
+	n := 0
+	for _, frame := range frames {
+		if n == maxFingerprintFrames {
+			break
+		}
+		frame, keep := normalizeFrame(frame, rules)
+		if !keep {
+			continue
+		}
+		n++
+
+		parts := []string{frame.Function, filepath.Base(frame.File)}
+		if !ignoreLines {
+			parts = append(parts, strconv.Itoa(frame.Line))
+		}
+		h.Write([]byte{0})
+		h.Write([]byte(strings.Join(parts, ":")))
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+func normalizeFrame(frame StackFrame, rules []FrameRule) (StackFrame, bool) {
+	for _, rule := range rules {
+		var keep bool
+		if frame, keep = rule(frame); !keep {
+			return frame, false
+		}
+	}
+	return frame, true
+}
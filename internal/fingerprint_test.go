@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestMessageTemplate(t *testing.T) {
+	got := MessageTemplate("order 4821 for user 7f1c2d3e-aaaa-4bbb-8ccc-123456789abc failed after 1.5s (trace 4bf92f3577b34da6)")
+	want := "order <n> for user <uuid> failed after <n>s (trace <hex>)"
+	if got != want {
+		t.Errorf("MessageTemplate() = %q, want %q", got, want)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	frames := []StackFrame{
+		{Function: "main.handler", File: "/build/a/app/main.go", Line: 10},
+		{Function: "main.main", File: "/build/a/app/main.go", Line: 42},
+	}
+	moved := []StackFrame{
+		{Function: "main.handler", File: "/home/dev/app/main.go", Line: 10},
+		{Function: "main.main", File: "/home/dev/app/main.go", Line: 42},
+	}
+
+	a := Fingerprint("NotExist", "open /data/1234.json: no such file", frames)
+	if b := Fingerprint("NotExist", "open /data/9876.json: no such file", moved); a != b {
+		t.Error("same failure with other IDs and build paths got different fingerprints")
+	}
+	if b := Fingerprint("Timeout", "open /data/1234.json: no such file", frames); a == b {
+		t.Error("different classes share a fingerprint")
+	}
+
+	drifted := []StackFrame{frames[0], {Function: "main.main", File: "main.go", Line: 45}}
+	if Fingerprint("NotExist", "x", frames) == Fingerprint("NotExist", "x", drifted) {
+		t.Error("line numbers are ignored by default")
+	}
+
+	defer func() {
+		messageRules, frameRules, ignoreLineNumbers = nil, nil, false
+	}()
+	SetFingerprintIgnoreLineNumbers(true)
+	if Fingerprint("NotExist", "x", frames) != Fingerprint("NotExist", "x", drifted) {
+		t.Error("line drift changed the fingerprint")
+	}
+}
+
+func TestFingerprintRules(t *testing.T) {
+	defer func() {
+		messageRules, frameRules, ignoreLineNumbers = nil, nil, false
+	}()
+
+	AddFingerprintMessageRule(MessageRule{regexp.MustCompile(`\S+@\S+`), "<email>"})
+	if got := MessageTemplate("unknown user bob@example.com"); got != "unknown user <email>" {
+		t.Errorf("MessageTemplate() = %q", got)
+	}
+
+	AddFingerprintFrameRule(func(f StackFrame) (StackFrame, bool) {
+		return f, !strings.HasPrefix(f.Function, "middleware.")
+	})
+	direct := []StackFrame{{Function: "main.handler", File: "main.go", Line: 1}}
+	wrapped := append([]StackFrame{{Function: "middleware.Recover", File: "mw.go", Line: 7}}, direct...)
+	if Fingerprint("E", "m", direct) != Fingerprint("E", "m", wrapped) {
+		t.Error("frame rule was not applied")
+	}
+}
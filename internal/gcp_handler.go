@@ -0,0 +1,181 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strconv"
+)
+
+// Special fields recognized by the Cloud Logging agents in structured JSON payloads.
+const (
+	gcpTraceKey          = "logging.googleapis.com/trace"
+	gcpSpanIDKey         = "logging.googleapis.com/spanId"
+	gcpSourceLocationKey = "logging.googleapis.com/sourceLocation"
+	gcpLabelsKey         = "logging.googleapis.com/labels"
+	gcpTraceSampledKey   = "logging.googleapis.com/trace_sampled"
+)
+
+// GCPHandler is a slog.Handler that prints the "event" field as a Google Cloud
+// Logging structured JSON line, so that GKE and Cloud Run parse severity, trace
+// correlation, source location and HTTP request details.
+type GCPHandler struct {
+	writer    io.Writer
+	projectID string
+}
+
+// NewGCPHandler creates a new GCPHandler. projectID is used to build fully
+// qualified trace names; when empty, trace IDs are emitted as-is.
+func NewGCPHandler(w io.Writer, projectID string) *GCPHandler {
+	return &GCPHandler{writer: w, projectID: projectID}
+}
+
+func (h *GCPHandler) Enabled(_ context.Context, _ slog.Level) bool {
+	return true
+}
+
+func (h *GCPHandler) Handle(_ context.Context, record slog.Record) error {
+	event := ExtractEvent(record)
+	if event == nil {
+		return nil // nothing to log
+	}
+
+	fields, err := EventFields(event)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(h.writer)
+	return enc.Encode(h.payload(fields, record))
+}
+
+func (h *GCPHandler) WithAttrs(_ []slog.Attr) slog.Handler {
+	// no-op, stateless handler
+	return h
+}
+
+func (h *GCPHandler) WithGroup(_ string) slog.Handler {
+	// no-op, stateless handler
+	return h
+}
+
+func (h *GCPHandler) payload(fields map[string]any, record slog.Record) map[string]any {
+	payload := make(map[string]any, len(fields)+4)
+	for k, v := range fields {
+		payload[k] = v
+	}
+
+	delete(payload, "level")
+	payload["severity"] = gcpSeverity(record.Level)
+
+	if ts, ok := payload["timestamp"]; ok {
+		delete(payload, "timestamp")
+		payload["time"] = ts
+	}
+
+	if traceID, _ := fields["trace_id"].(string); traceID != "" {
+		delete(payload, "trace_id")
+		if h.projectID != "" {
+			payload[gcpTraceKey] = fmt.Sprintf("projects/%s/traces/%s", h.projectID, traceID)
+		} else {
+			payload[gcpTraceKey] = traceID
+		}
+	}
+	if sampled, ok := fields["trace_sampled"].(bool); ok {
+		delete(payload, "trace_sampled")
+		payload[gcpTraceSampledKey] = sampled
+	}
+	if spanID, _ := fields["span_id"].(string); spanID != "" {
+		delete(payload, "span_id")
+		payload[gcpSpanIDKey] = spanID
+	}
+
+	if eventType, _ := fields["event_type"].(string); eventType != "" {
+		payload[gcpLabelsKey] = map[string]string{"event_type": eventType}
+	}
+
+	if loc := gcpSourceLocation(record.PC); loc != nil {
+		payload[gcpSourceLocationKey] = loc
+	}
+
+	if req := gcpHTTPRequest(fields); req != nil {
+		payload["httpRequest"] = req
+	}
+
+	return payload
+}
+
+// gcpSeverity maps a slog level onto a Cloud Logging severity.
+//
+// Ranges are used rather than exact values so that levels placed between the
+// standard ones still get a sensible severity.
+func gcpSeverity(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "DEBUG"
+	case level == slog.LevelInfo:
+		return "INFO"
+	case level < slog.LevelWarn:
+		return "NOTICE"
+	case level < slog.LevelError:
+		return "WARNING"
+	case level < slog.LevelError+4:
+		return "ERROR"
+	case level < slog.LevelError+8:
+		return "CRITICAL"
+	case level < slog.LevelError+12:
+		return "ALERT"
+	default:
+		return "EMERGENCY"
+	}
+}
+
+func gcpSourceLocation(pc uintptr) map[string]string {
+	if pc == 0 {
+		return nil
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.File == "" {
+		return nil
+	}
+	return map[string]string{
+		"file":     frame.File,
+		"line":     strconv.Itoa(frame.Line),
+		"function": frame.Function,
+	}
+}
+
+// gcpHTTPRequest builds the HttpRequest object for operation entries, or nil.
+func gcpHTTPRequest(fields map[string]any) map[string]any {
+	req := map[string]any{}
+
+	switch fields["event_type"] {
+	case "OperationRequestLogEntry":
+		if method, _ := fields["http_method"].(string); method != "" {
+			req["requestMethod"] = method
+		}
+		if path, _ := fields["path"].(string); path != "" {
+			req["requestUrl"] = path
+		}
+	case "OperationResponseLogEntry":
+		if status, ok := fields["http_status"].(json.Number); ok {
+			if n, err := status.Int64(); err == nil && n != 0 {
+				req["status"] = n
+			}
+		}
+		if duration, ok := fields["duration_ms"].(json.Number); ok {
+			if ms, err := duration.Int64(); err == nil {
+				// google.protobuf.Duration JSON encoding
+				req["latency"] = strconv.FormatFloat(float64(ms)/1000, 'f', -1, 64) + "s"
+			}
+		}
+	}
+
+	if len(req) == 0 {
+		return nil
+	}
+	return req
+}
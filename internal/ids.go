@@ -0,0 +1,31 @@
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// NewTraceID returns a random, non-zero 128-bit trace ID as 32 lowercase hex digits.
+func NewTraceID() string {
+	return randomID(16)
+}
+
+// NewSpanID returns a random, non-zero 64-bit span ID as 16 lowercase hex digits.
+func NewSpanID() string {
+	return randomID(8)
+}
+
+// randomID returns size random bytes as hex, drawing again in the (practically
+// impossible) all-zero case, which W3C Trace Context and B3 treat as invalid.
+func randomID(size int) string {
+	b := make([]byte, size)
+	for {
+		// crypto/rand.Read never returns an error
+		_, _ = rand.Read(b)
+		for _, c := range b {
+			if c != 0 {
+				return hex.EncodeToString(b)
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// logfmtLeadingKeys are always emitted first, in this order, to keep lines scannable.
+var logfmtLeadingKeys = []string{"timestamp", "level", "event_type", "message"}
+
+// LogfmtHandler is a slog.Handler that prints the "event" field as a single logfmt line.
+//
+// Field names follow the JSON tags of the entry types. Nested objects such as
+// additional_data are flattened into dotted keys (e.g. additional_data.user.id).
+type LogfmtHandler struct {
+	writer io.Writer
+}
+
+// NewLogfmtHandler creates a new LogfmtHandler.
+func NewLogfmtHandler(w io.Writer) *LogfmtHandler {
+	return &LogfmtHandler{writer: w}
+}
+
+func (h *LogfmtHandler) Enabled(_ context.Context, _ slog.Level) bool {
+	return true
+}
+
+func (h *LogfmtHandler) Handle(_ context.Context, record slog.Record) error {
+	event := ExtractEvent(record)
+	if event == nil {
+		return nil // nothing to log
+	}
+
+	fields, err := EventFields(event)
+	if err != nil {
+		return err
+	}
+
+	flat := map[string]string{}
+	flattenLogfmt(flat, "", fields)
+
+	var b strings.Builder
+	for _, key := range orderedLogfmtKeys(flat) {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(flat[key])
+	}
+	b.WriteByte('\n')
+
+	_, err = io.WriteString(h.writer, b.String())
+	return err
+}
+
+func (h *LogfmtHandler) WithAttrs(_ []slog.Attr) slog.Handler {
+	// no-op, stateless handler
+	return h
+}
+
+func (h *LogfmtHandler) WithGroup(_ string) slog.Handler {
+	// no-op, stateless handler
+	return h
+}
+
+// flattenLogfmt stores every leaf of value in dst, keyed by its dotted path and
+// already quoted for logfmt.
+func flattenLogfmt(dst map[string]string, prefix string, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, nested := range v {
+			key := logfmtKey(k)
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			flattenLogfmt(dst, key, nested)
+		}
+	case nil:
+		// absent values are omitted
+	case string:
+		dst[prefix] = logfmtValue(v)
+	case json.Number:
+		dst[prefix] = v.String()
+	case bool:
+		dst[prefix] = strconv.FormatBool(v)
+	default:
+		// arrays keep their JSON representation
+		data, err := json.Marshal(v)
+		if err != nil {
+			dst[prefix] = logfmtValue(fmt.Sprintf("%v", v))
+			return
+		}
+		dst[prefix] = logfmtValue(string(data))
+	}
+}
+
+// orderedLogfmtKeys returns the leading keys first and the remaining ones sorted.
+func orderedLogfmtKeys(fields map[string]string) []string {
+	keys := make([]string, 0, len(fields))
+	seen := make(map[string]bool, len(logfmtLeadingKeys))
+	for _, k := range logfmtLeadingKeys {
+		if _, ok := fields[k]; ok {
+			keys = append(keys, k)
+			seen[k] = true
+		}
+	}
+
+	rest := make([]string, 0, len(fields))
+	for k := range fields {
+		if !seen[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+// logfmtKey replaces characters that would break key parsing.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == unicode.ReplacementChar {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtValue quotes a value when it is empty or contains spaces, quotes,
+// equal signs or non-printable characters.
+func logfmtValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r) {
+			return strconv.Quote(value)
+		}
+	}
+	return value
+}
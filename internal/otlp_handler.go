@@ -0,0 +1,331 @@
+package internal
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+// otlpReservedFields are entry fields mapped onto dedicated LogRecord, resource or
+// scope fields instead of attributes.
+var otlpReservedFields = map[string]bool{
+	"timestamp":          true,
+	"level":              true,
+	"message":            true,
+	"event_type":         true,
+	"trace_id":           true,
+	"span_id":            true,
+	"trace_sampled":      true,
+	"version":            true,
+	"commit_hash":        true,
+	"build_time":         true,
+	"library_name":       true,
+	"library_version":    true,
+	"library_commit":     true,
+	"library_build_time": true,
+	"additional_data":    true,
+	"context":            true,
+}
+
+// otlpAttributeNames renames entry fields that have an OpenTelemetry semantic convention.
+var otlpAttributeNames = map[string]string{
+	"error_class":   "exception.type",
+	"error_message": "exception.message",
+	"stack_trace":   "exception.stacktrace",
+}
+
+// The types below mirror the OTLP/JSON encoding of ExportLogsServiceRequest.
+// 64-bit integers are encoded as strings and trace/span IDs as lowercase hex,
+// as mandated by the OTLP JSON mapping.
+
+type otlpExportRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	EventName            string         `json:"eventName,omitempty"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	Flags                uint32         `json:"flags,omitempty"`
+	TraceID              string         `json:"traceId,omitempty"`
+	SpanID               string         `json:"spanId,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string        `json:"stringValue,omitempty"`
+	BoolValue   *bool          `json:"boolValue,omitempty"`
+	IntValue    *string        `json:"intValue,omitempty"`
+	DoubleValue *float64       `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArray     `json:"arrayValue,omitempty"`
+	KvlistValue *otlpKeyValues `json:"kvlistValue,omitempty"`
+}
+
+type otlpArray struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+type otlpKeyValues struct {
+	Values []otlpKeyValue `json:"values"`
+}
+
+// OTLPJSONHandler is a slog.Handler that prints each "event" as a single-line
+// OTLP/JSON ExportLogsServiceRequest holding one LogRecord.
+//
+// The output can be tailed by an OpenTelemetry collector (otlpjsonfile receiver)
+// or posted as-is to an OTLP/HTTP logs endpoint.
+type OTLPJSONHandler struct {
+	writer io.Writer
+}
+
+// NewOTLPJSONHandler creates a new OTLPJSONHandler.
+func NewOTLPJSONHandler(w io.Writer) *OTLPJSONHandler {
+	return &OTLPJSONHandler{writer: w}
+}
+
+func (h *OTLPJSONHandler) Enabled(_ context.Context, _ slog.Level) bool {
+	return true
+}
+
+func (h *OTLPJSONHandler) Handle(_ context.Context, record slog.Record) error {
+	event := ExtractEvent(record)
+	if event == nil {
+		return nil // nothing to log
+	}
+
+	fields, err := EventFields(event)
+	if err != nil {
+		return err
+	}
+
+	request := otlpExportRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource: otlpResource{Attributes: otlpResourceAttributes(fields)},
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScopeFrom(fields),
+				LogRecords: []otlpLogRecord{otlpRecordFrom(event, fields, record)},
+			}},
+		}},
+	}
+
+	enc := json.NewEncoder(h.writer)
+	return enc.Encode(request)
+}
+
+func (h *OTLPJSONHandler) WithAttrs(_ []slog.Attr) slog.Handler {
+	// no-op, stateless handler
+	return h
+}
+
+func (h *OTLPJSONHandler) WithGroup(_ string) slog.Handler {
+	// no-op, stateless handler
+	return h
+}
+
+func otlpRecordFrom(event any, fields map[string]any, record slog.Record) otlpLogRecord {
+	level := Level.Info
+	if e, ok := event.(interface{ GetLevel() Level.LogLevel }); ok {
+		level = e.GetLevel()
+	}
+
+	timestamp := record.Time
+	if s, ok := fields["timestamp"].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			timestamp = t
+		}
+	}
+	observed := record.Time
+	if observed.IsZero() {
+		observed = time.Now()
+	}
+
+	message, _ := fields["message"].(string)
+	eventType, _ := fields["event_type"].(string)
+
+	attrs := map[string]any{}
+	for _, key := range []string{"additional_data", "context"} {
+		if data, ok := fields[key].(map[string]any); ok {
+			for k, v := range data {
+				attrs[k] = v
+			}
+		}
+	}
+	for k, v := range fields {
+		if otlpReservedFields[k] {
+			continue
+		}
+		if k == "stack_trace" {
+			v = FormatStackTrace(v)
+		}
+		if name, ok := otlpAttributeNames[k]; ok {
+			k = name
+		}
+		attrs[k] = v
+	}
+
+	out := otlpLogRecord{
+		TimeUnixNano:         strconv.FormatInt(timestamp.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(observed.UnixNano(), 10),
+		SeverityNumber:       otlpSeverityNumber(level, record.Level),
+		SeverityText:         strings.ToUpper(string(level)),
+		EventName:            eventType,
+		Body:                 otlpValue(message),
+	}
+
+	// opaque IDs that are not valid W3C identifiers are kept as attributes
+	if id, _ := fields["trace_id"].(string); isHexID(id, 16) {
+		out.TraceID = strings.ToLower(id)
+	} else if id != "" {
+		attrs["trace_id"] = id
+	}
+	if sampled, _ := fields["trace_sampled"].(bool); sampled && out.TraceID != "" {
+		out.Flags = 0x01 // W3C sampled trace flag
+	}
+	if id, _ := fields["span_id"].(string); isHexID(id, 8) {
+		out.SpanID = strings.ToLower(id)
+	} else if id != "" {
+		attrs["span_id"] = id
+	}
+
+	out.Attributes = otlpKeyValuesFrom(attrs)
+	return out
+}
+
+func otlpResourceAttributes(fields map[string]any) []otlpKeyValue {
+	attrs := map[string]any{}
+	if v, ok := fields["version"]; ok {
+		attrs["service.version"] = v
+	}
+	if v, ok := fields["commit_hash"]; ok {
+		attrs["vcs.ref.head.revision"] = v
+	}
+	if v, ok := fields["build_time"]; ok {
+		attrs["build.time"] = v
+	}
+	return otlpKeyValuesFrom(attrs)
+}
+
+func otlpScopeFrom(fields map[string]any) otlpScope {
+	name, _ := fields["library_name"].(string)
+	version, _ := fields["library_version"].(string)
+	if name == "" {
+		name = LibraryName
+		version = LibraryVersion
+	}
+	return otlpScope{Name: name, Version: version}
+}
+
+// otlpSeverityNumber maps a chronolog level to the OpenTelemetry severity number.
+//
+// The slog level is translated with the same offset used by the OpenTelemetry slog
+// bridge (Info = 9, Warn = 13, Error = 17), so any level mapped onto slog gets a
+// consistent severity. Trace is special-cased because slog has no trace level.
+func otlpSeverityNumber(level Level.LogLevel, slogLevel slog.Level) int {
+	if level == Level.Trace {
+		return 1
+	}
+	n := int(slogLevel) + 9
+	return min(max(n, 1), 24)
+}
+
+func otlpKeyValuesFrom(attrs map[string]any) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]otlpKeyValue, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, otlpKeyValue{Key: k, Value: otlpValue(attrs[k])})
+	}
+	return out
+}
+
+func otlpValue(v any) otlpAnyValue {
+	switch val := v.(type) {
+	case string:
+		return otlpAnyValue{StringValue: &val}
+	case bool:
+		return otlpAnyValue{BoolValue: &val}
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			s := strconv.FormatInt(i, 10)
+			return otlpAnyValue{IntValue: &s}
+		}
+		if f, err := val.Float64(); err == nil {
+			return otlpAnyValue{DoubleValue: &f}
+		}
+		s := val.String()
+		return otlpAnyValue{StringValue: &s}
+	case []any:
+		values := make([]otlpAnyValue, 0, len(val))
+		for _, item := range val {
+			values = append(values, otlpValue(item))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArray{Values: values}}
+	case map[string]any:
+		values := otlpKeyValuesFrom(val)
+		if values == nil {
+			values = []otlpKeyValue{}
+		}
+		return otlpAnyValue{KvlistValue: &otlpKeyValues{Values: values}}
+	default:
+		return otlpAnyValue{}
+	}
+}
+
+// isHexID reports whether id is the hex encoding of a non-zero identifier of n bytes.
+func isHexID(id string, n int) bool {
+	if len(id) != 2*n {
+		return false
+	}
+	b, err := hex.DecodeString(id)
+	if err != nil {
+		return false
+	}
+	for _, c := range b {
+		if c != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -9,6 +9,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	Level "github.com/Astronotify/chronolog/level"
 )
 
 type PrettyConsoleHandler struct {
@@ -40,6 +42,10 @@ func (h *PrettyConsoleHandler) Handle(_ context.Context, record slog.Record) err
 
 	timestamp := time.Now().UTC().Format(time.RFC3339)
 	level := strings.ToUpper(record.Level.String())
+	// slog has no name for levels above Error, such as panic and fatal
+	if e, ok := event.(interface{ GetLevel() Level.LogLevel }); ok && e.GetLevel() != "" {
+		level = strings.ToUpper(string(e.GetLevel()))
+	}
 	typeName := reflect.TypeOf(event).Name()
 	if typeName == "" {
 		typeName = "LogEntry"
@@ -112,10 +118,22 @@ func summarizeAllFields(event any) string {
 			}
 		case int, int64, float64, bool:
 			fields[key] = fmt.Sprintf("%v", v)
+		case *bool:
+			if v != nil {
+				fields[key] = fmt.Sprintf("%v", *v)
+			}
+		case []StackFrame:
+			if len(v) > 0 {
+				fields[key] = fmt.Sprintf("%v", v)
+			}
 		case map[string]any:
 			if len(v) > 0 {
 				fields[key] = fmt.Sprintf("%v", v)
 			}
+		case map[string]string:
+			if len(v) > 0 {
+				fields[key] = summarizeStringMap(v)
+			}
 		default:
 			// Tenta fallback razoável
 			fields[key] = fmt.Sprintf("%v", v)
@@ -137,3 +155,18 @@ func summarizeAllFields(event any) string {
 
 	return strings.Join(parts, "  ")
 }
+
+// summarizeStringMap renders a map as sorted k=v pairs, such as baggage members.
+func summarizeStringMap(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+m[k])
+	}
+	return "map[" + strings.Join(pairs, " ") + "]"
+}
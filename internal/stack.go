@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// maxStackDepth bounds the number of frames captured for a stack trace.
+const maxStackDepth = 64
+
+// StackFrame is one resolved frame of a stack trace.
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// String formats the frame as "function (file:line)".
+func (f StackFrame) String() string {
+	return fmt.Sprintf("%s (%s:%d)", f.Function, f.File, f.Line)
+}
+
+// Callers captures the program counters of the calling goroutine, skipping skip
+// frames above the caller of Callers.
+func Callers(skip int) []uintptr {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	return append([]uintptr(nil), pcs[:n]...)
+}
+
+// ExtractStackTrace returns the stack trace recorded where err was created.
+//
+// The deepest error of the Unwrap chain exposing a StackTrace() method returning
+// a slice of program counters is used; this covers chronolog/errors as well as
+// github.com/pkg/errors. When no error carries a stack, the stack of the calling
+// goroutine is captured instead. Chronolog frames are trimmed in both cases.
+func ExtractStackTrace(err error) []StackFrame {
+	var pcs []uintptr
+	for depth := 0; err != nil && depth < maxUnwrapDepth; depth++ {
+		if stack, ok := stackOf(err); ok {
+			pcs = stack
+		}
+		err = errors.Unwrap(err)
+	}
+	if pcs == nil {
+		pcs = Callers(1)
+	}
+	return ResolveFrames(pcs)
+}
+
+// stackOf reads the program counters returned by a StackTrace() method, if any.
+//
+// Reflection is used so that any named slice of uintptr-kind elements is accepted
+// (e.g. pkg/errors.StackTrace, a []Frame where Frame is a uintptr).
+func stackOf(err error) ([]uintptr, bool) {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() {
+		return nil, false
+	}
+	mt := method.Type()
+	if mt.NumIn() != 0 || mt.NumOut() != 1 {
+		return nil, false
+	}
+	out := mt.Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil, false
+	}
+
+	stack := method.Call(nil)[0]
+	pcs := make([]uintptr, stack.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(stack.Index(i).Uint())
+	}
+	return pcs, len(pcs) > 0
+}
+
+// ResolveFrames converts program counters into frames, dropping chronolog and
+// Go runtime frames.
+func ResolveFrames(pcs []uintptr) []StackFrame {
+	if len(pcs) == 0 {
+		return nil
+	}
+	var out []StackFrame
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" && !IsLibraryFunction(frame.Function) && !strings.HasPrefix(frame.Function, "runtime.") {
+			out = append(out, StackFrame{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+			})
+		}
+		if !more {
+			break
+		}
+	}
+	return out
+}
+
+// FormatStackTrace renders the "stack_trace" value of an entry's JSON fields as
+// text, one frame per line, for formats that expect a string.
+func FormatStackTrace(value any) string {
+	frames, ok := value.([]any)
+	if !ok {
+		s, _ := value.(string)
+		return s
+	}
+	var b strings.Builder
+	for _, f := range frames {
+		frame, _ := f.(map[string]any)
+		fmt.Fprintf(&b, "%v\n\t%v:%v\n", frame["function"], frame["file"], frame["line"])
+	}
+	return b.String()
+}
@@ -1,8 +1,10 @@
 package internal
 
 import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
 	"reflect"
-	"runtime/debug"
 )
 
 func MergeAdditionalData(data ...map[string]any) map[string]any {
@@ -15,14 +17,6 @@ func MergeAdditionalData(data ...map[string]any) map[string]any {
 	return merged
 }
 
-func ClassifyError(err error) string {
-	return "GenericError"
-}
-
-func ExtractStackTrace(err error) string {
-	return string(debug.Stack())
-}
-
 func GetStructName(i interface{}) string {
 	t := reflect.TypeOf(i)
 	if t.Kind() == reflect.Pointer {
@@ -30,3 +24,44 @@ func GetStructName(i interface{}) string {
 	}
 	return t.Name()
 }
+
+// ExtractEvent returns the value of the "event" attribute of a record, or nil if absent.
+func ExtractEvent(record slog.Record) any {
+	var event any
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "event" {
+			event = attr.Value.Any()
+			return false
+		}
+		return true
+	})
+	return event
+}
+
+// EventFields converts an entry into a generic map keyed by its JSON field names.
+//
+// Entries are round-tripped through encoding/json so that every encoder shares the
+// naming, omitempty rules and custom marshalers of the JSON format. Numbers are
+// decoded as json.Number to preserve their exact representation.
+func EventFields(event any) (map[string]any, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var fields map[string]any
+	if err := dec.Decode(&fields); err != nil {
+		// not a JSON object (e.g. a plain string entry)
+		var value any
+		dec = json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		return map[string]any{"message": value}, nil
+	}
+	return fields, nil
+}
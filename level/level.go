@@ -11,6 +11,8 @@ package Level
 //   - Info:  for standard informational messages about application behavior.
 //   - Warn:  for potentially problematic situations that require attention.
 //   - Error: for serious issues indicating failures in execution.
+//   - Panic: for failures after which the current goroutine panics.
+//   - Fatal: for failures after which the process exits.
 type LogLevel string
 
 const (
@@ -33,12 +35,27 @@ const (
 	// Error indicates a serious failure that prevents the application or request from continuing as expected.
 	// These logs typically require investigation or alerting.
 	Error LogLevel = "error"
+
+	// Panic indicates a failure the program cannot recover from at this point.
+	// chronolog.Panic flushes every sink before panicking with the logged error.
+	Panic LogLevel = "panic"
+
+	// Fatal indicates a failure that terminates the process.
+	// chronolog.Fatal flushes every sink before exiting with status 1.
+	Fatal LogLevel = "fatal"
 )
 
+// LogLevelPriority holds the order of the built-in levels. Higher values are more
+// severe.
+//
+// It does not know about levels added with Register: compare levels with
+// Priority, whose scale leaves room between the built-in levels.
 var LogLevelPriority = map[LogLevel]int{
 	"trace": 0,
 	"debug": 1,
 	"info":  2,
 	"warn":  3,
 	"error": 4,
+	"panic": 5,
+	"fatal": 6,
 }
@@ -0,0 +1,112 @@
+package Level
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidLevel is returned by Register for empty names or names already in use.
+var ErrInvalidLevel = errors.New("invalid log level")
+
+// builtinPriorityStep spaces the Priority of built-in levels, derived from
+// LogLevelPriority, so that registered levels can be placed between them.
+const builtinPriorityStep = 10
+
+// levelInfo describes how a level is filtered and handed to slog.
+type levelInfo struct {
+	priority  int
+	slogLevel slog.Level
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[LogLevel]levelInfo{
+		Trace: {LogLevelPriority[Trace] * builtinPriorityStep, slog.LevelDebug},
+		Debug: {LogLevelPriority[Debug] * builtinPriorityStep, slog.LevelDebug},
+		Info:  {LogLevelPriority[Info] * builtinPriorityStep, slog.LevelInfo},
+		Warn:  {LogLevelPriority[Warn] * builtinPriorityStep, slog.LevelWarn},
+		Error: {LogLevelPriority[Error] * builtinPriorityStep, slog.LevelError},
+		Panic: {LogLevelPriority[Panic] * builtinPriorityStep, slog.LevelError + 4},
+		Fatal: {LogLevelPriority[Fatal] * builtinPriorityStep, slog.LevelError + 8},
+	}
+)
+
+// Register adds a custom level, such as "notice" or "audit".
+//
+// The priority decides filtering against minimum levels and uses the scale of
+// Priority, on which the built-in levels are spaced by 10 (trace 0, debug 10,
+// info 20, warn 30, error 40, panic 50, fatal 60); any value is accepted. The slog
+// level decides how formatters map the level onto their own severities (e.g.
+// Cloud Logging NOTICE or OTLP severity numbers).
+// It is safe to call concurrently with logging, typically from an init function.
+//
+// Parameters:
+//
+//   - level (LogLevel): the level name, lowercase by convention. Must not be empty or already registered.
+//   - priority (int): the filtering priority on the Priority scale; higher is more severe.
+//   - slogLevel (slog.Level): the slog level records of this level are emitted with.
+//
+// Returns:
+//
+//   - error: an error wrapping ErrInvalidLevel if the name is empty or already taken.
+func Register(level LogLevel, priority int, slogLevel slog.Level) error {
+	if strings.TrimSpace(string(level)) == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidLevel)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[level]; exists {
+		return fmt.Errorf("%w: %q is already registered", ErrInvalidLevel, level)
+	}
+	registry[level] = levelInfo{priority: priority, slogLevel: slogLevel}
+	return nil
+}
+
+// Priority returns the filtering priority of a built-in or registered level; it
+// is the API to compare levels. Built-in levels have 10 times their
+// LogLevelPriority. The boolean is false for unknown levels.
+func Priority(level LogLevel) (int, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	info, ok := registry[level]
+	return info.priority, ok
+}
+
+// SlogLevel returns the slog level of a built-in or registered level.
+// The boolean is false for unknown levels.
+func SlogLevel(level LogLevel) (slog.Level, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	info, ok := registry[level]
+	return info.slogLevel, ok
+}
+
+// IsKnown reports whether level is built-in or registered.
+func IsKnown(level LogLevel) bool {
+	_, ok := Priority(level)
+	return ok
+}
+
+// Levels returns every built-in and registered level, ordered by priority.
+func Levels() []LogLevel {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	levels := make([]LogLevel, 0, len(registry))
+	for level := range registry {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		pi, pj := registry[levels[i]].priority, registry[levels[j]].priority
+		if pi != pj {
+			return pi < pj
+		}
+		return levels[i] < levels[j]
+	})
+	return levels
+}
@@ -0,0 +1,186 @@
+package chronolog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+// levelState holds the minimum levels shared by a Logger and every logger derived
+// from it with Named. Reads are lock-free; updates replace the whole snapshot.
+type levelState struct {
+	mu      sync.Mutex // serializes updates
+	current atomic.Pointer[levelSnapshot]
+}
+
+type levelSnapshot struct {
+	minimum Level.LogLevel
+	// overrides maps normalized logger name prefixes to their minimum level.
+	overrides map[string]Level.LogLevel
+}
+
+func newLevelState(minimum Level.LogLevel, overrides map[string]Level.LogLevel) *levelState {
+	s := &levelState{}
+	normalized := make(map[string]Level.LogLevel, len(overrides))
+	for pattern, level := range overrides {
+		if Level.IsKnown(level) {
+			normalized[NormalizeLoggerPattern(pattern)] = level
+		}
+	}
+	s.current.Store(&levelSnapshot{minimum: minimum, overrides: normalized})
+	return s
+}
+
+// levelFor returns the minimum level applying to the named logger: the level of
+// the longest matching prefix, or the global minimum when none matches.
+func (s *levelState) levelFor(name string) Level.LogLevel {
+	snapshot := s.current.Load()
+	level, matched := snapshot.minimum, -1
+	for prefix, override := range snapshot.overrides {
+		if len(prefix) > matched && matchesLoggerPrefix(name, prefix) {
+			level, matched = override, len(prefix)
+		}
+	}
+	return level
+}
+
+// update applies fn to a copy of the current snapshot and publishes it.
+func (s *levelState) update(fn func(*levelSnapshot)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.current.Load()
+	next := &levelSnapshot{
+		minimum:   current.minimum,
+		overrides: make(map[string]Level.LogLevel, len(current.overrides)),
+	}
+	for prefix, level := range current.overrides {
+		next.overrides[prefix] = level
+	}
+	fn(next)
+	s.current.Store(next)
+}
+
+// NormalizeLoggerPattern returns the logger name prefix a level pattern applies to:
+// "payments/*", "payments.*" and "payments" all become "payments". It is the key
+// used by Logger.LoggerLevels.
+func NormalizeLoggerPattern(pattern string) string {
+	pattern = strings.TrimSuffix(pattern, "*")
+	return strings.TrimRight(pattern, "./")
+}
+
+// matchesLoggerPrefix reports whether name is prefix itself or one of its
+// descendants, segments being separated by "." or "/".
+func matchesLoggerPrefix(name, prefix string) bool {
+	if prefix == "" || name == prefix {
+		return true
+	}
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	sep := name[len(prefix)]
+	return sep == '.' || sep == '/'
+}
+
+func validateLevel(level Level.LogLevel) error {
+	if !Level.IsKnown(level) {
+		return fmt.Errorf("%w: unknown level %q", Level.ErrInvalidLevel, level)
+	}
+	return nil
+}
+
+// MinimumLevel returns the global minimum level of the logger, which applies to
+// loggers whose name matches no override.
+func (l *Logger) MinimumLevel() Level.LogLevel {
+	return l.base().levels.current.Load().minimum
+}
+
+// SetMinimumLevel changes the global minimum level at runtime. The change is
+// visible to the logger and to every logger derived from it with Named.
+//
+// Parameters:
+//   - level (Level.LogLevel): a built-in or registered level.
+//
+// Returns:
+//   - error: an error wrapping Level.ErrInvalidLevel if the level is unknown.
+func (l *Logger) SetMinimumLevel(level Level.LogLevel) error {
+	if err := validateLevel(level); err != nil {
+		return err
+	}
+	l.base().levels.update(func(s *levelSnapshot) { s.minimum = level })
+	return nil
+}
+
+// LoggerLevels returns a copy of the per-name minimum levels, keyed by normalized
+// prefix (e.g. "payments" for the pattern "payments/*").
+func (l *Logger) LoggerLevels() map[string]Level.LogLevel {
+	overrides := l.base().levels.current.Load().overrides
+	out := make(map[string]Level.LogLevel, len(overrides))
+	for prefix, level := range overrides {
+		out[prefix] = level
+	}
+	return out
+}
+
+// SetLoggerLevel sets the minimum level of the loggers matching pattern at runtime.
+//
+// The pattern is a logger name prefix, optionally followed by "/*" or ".*":
+// "payments" matches "payments", "payments.ledger" and "payments/refunds". When
+// several patterns match a logger, the longest one wins.
+//
+// Parameters:
+//   - pattern (string): the logger name prefix.
+//   - level (Level.LogLevel): a built-in or registered level, or "" to remove the override.
+//
+// Returns:
+//   - error: an error wrapping Level.ErrInvalidLevel if the level is unknown.
+func (l *Logger) SetLoggerLevel(pattern string, level Level.LogLevel) error {
+	if level != "" {
+		if err := validateLevel(level); err != nil {
+			return err
+		}
+	}
+	prefix := NormalizeLoggerPattern(pattern)
+	l.base().levels.update(func(s *levelSnapshot) {
+		if level == "" {
+			delete(s.overrides, prefix)
+		} else {
+			s.overrides[prefix] = level
+		}
+	})
+	return nil
+}
+
+// SetLoggerLevels replaces every per-name minimum level at once. See SetLoggerLevel
+// for the pattern syntax.
+//
+// Parameters:
+//   - levels (map[string]Level.LogLevel): the new overrides, keyed by pattern. Nil removes them all.
+//
+// Returns:
+//   - error: an error wrapping Level.ErrInvalidLevel if any level is unknown, in
+//     which case nothing is changed.
+func (l *Logger) SetLoggerLevels(levels map[string]Level.LogLevel) error {
+	patterns := make([]string, 0, len(levels))
+	for pattern := range levels {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if err := validateLevel(levels[pattern]); err != nil {
+			return fmt.Errorf("logger %q: %w", pattern, err)
+		}
+	}
+
+	l.base().levels.update(func(s *levelSnapshot) {
+		s.overrides = make(map[string]Level.LogLevel, len(levels))
+		for pattern, level := range levels {
+			s.overrides[NormalizeLoggerPattern(pattern)] = level
+		}
+	})
+	return nil
+}
@@ -0,0 +1,430 @@
+package chronolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EnvConfig       = "CHRONOLOG_CONFIG"
+	EnvFormat       = "CHRONOLOG_FORMAT"
+	EnvLevel        = "CHRONOLOG_LEVEL"
+	EnvOutput       = "CHRONOLOG_OUTPUT"
+	EnvLoggerLevels = "CHRONOLOG_LOGGER_LEVELS"
+	EnvBaggageKeys  = "CHRONOLOG_BAGGAGE_KEYS"
+)
+
+// ConfigError reports an invalid configuration value. Key names the offending
+// setting: a JSON path such as "sinks[1].format" or an environment variable name.
+type ConfigError struct {
+	Key string
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("chronolog config: %s: %v", e.Key, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// fileConfig is the JSON representation of Config read by LoadConfig.
+type fileConfig struct {
+	Format       Format                    `json:"format"`
+	Level        Level.LogLevel            `json:"level"`
+	Output       string                    `json:"output"`
+	LoggerLevels map[string]Level.LogLevel `json:"logger_levels"`
+	Async        *fileAsyncConfig          `json:"async"`
+	Sinks        []fileSinkConfig          `json:"sinks"`
+	BaggageKeys  []string                  `json:"baggage_keys"`
+	GCPProjectID string                    `json:"gcp_project_id"`
+	Signals      bool                      `json:"handle_signals"`
+}
+
+type fileAsyncConfig struct {
+	QueueSize      int            `json:"queue_size"`
+	OverflowPolicy OverflowPolicy `json:"overflow_policy"`
+	DropBelowLevel Level.LogLevel `json:"drop_below_level"`
+}
+
+type fileSinkConfig struct {
+	Format     Format           `json:"format"`
+	Level      Level.LogLevel   `json:"level"`
+	Output     string           `json:"output"`
+	EventTypes []string         `json:"event_types"`
+	Async      *fileAsyncConfig `json:"async"`
+}
+
+// LoadConfig reads a JSON configuration file.
+//
+// The file covers format, level, output, per-logger levels, asynchronous delivery,
+// sinks and baggage keys:
+//
+//	{
+//	  "format": "json",
+//	  "level": "info",
+//	  "output": "file:///var/log/app.log",
+//	  "logger_levels": {"payments/*": "debug"},
+//	  "baggage_keys": ["tenant", "customer_tier"]
+//	}
+//
+// Outputs are "stdout", "stderr", a file:// URL, appended to and created if
+// missing, or a URL whose scheme was registered with RegisterOutput.
+//
+// Unknown keys and invalid values are rejected rather than replaced by defaults.
+// Files opened for outputs are closed by Logger.Close.
+//
+// Parameters:
+//   - path (string): the path of the JSON file.
+//
+// Returns:
+//   - Config: the configuration, ready to be passed to New or Setup.
+//   - error: a *ConfigError naming the offending key, or the error reading the file.
+func LoadConfig(path string) (Config, error) {
+	fc, err := readConfigFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	return fc.build()
+}
+
+// ConfigFromEnv builds a configuration from CHRONOLOG_* environment variables.
+//
+// When CHRONOLOG_CONFIG names a file, it is loaded first with LoadConfig and the
+// other variables override its top-level settings:
+//
+//   - CHRONOLOG_FORMAT: json, pretty, logfmt, otlp_json, ecs, gcp or emf.
+//   - CHRONOLOG_LEVEL: the minimum level, such as debug or warn.
+//   - CHRONOLOG_OUTPUT: stdout, stderr, file:///path or a scheme registered with RegisterOutput.
+//   - CHRONOLOG_LOGGER_LEVELS: per-logger levels, e.g. "payments/*=debug,db=warn".
+//   - CHRONOLOG_BAGGAGE_KEYS: comma-separated baggage keys copied into entries.
+//
+// Returns:
+//   - Config: the configuration, ready to be passed to New or Setup.
+//   - error: a *ConfigError naming the offending variable or key.
+func ConfigFromEnv() (Config, error) {
+	fc := &fileConfig{}
+	if path := os.Getenv(EnvConfig); path != "" {
+		var err error
+		if fc, err = readConfigFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+
+	// keys set from the environment, so that errors name the variable
+	fromEnv := map[string]string{}
+
+	if v, ok := os.LookupEnv(EnvFormat); ok {
+		fc.Format = Format(v)
+		fromEnv["format"] = EnvFormat
+	}
+	if v, ok := os.LookupEnv(EnvLevel); ok {
+		fc.Level = Level.LogLevel(v)
+		fromEnv["level"] = EnvLevel
+	}
+	if v, ok := os.LookupEnv(EnvOutput); ok {
+		fc.Output = v
+		fromEnv["output"] = EnvOutput
+	}
+	if v, ok := os.LookupEnv(EnvLoggerLevels); ok {
+		levels, err := parseLoggerLevels(v)
+		if err != nil {
+			return Config{}, &ConfigError{Key: EnvLoggerLevels, Err: err}
+		}
+		fc.LoggerLevels = levels
+		fromEnv["logger_levels"] = EnvLoggerLevels
+	}
+	if v, ok := os.LookupEnv(EnvBaggageKeys); ok {
+		fc.BaggageKeys = splitList(v)
+		fromEnv["baggage_keys"] = EnvBaggageKeys
+	}
+
+	cfg, err := fc.build()
+	var cerr *ConfigError
+	if errors.As(err, &cerr) {
+		if env, ok := fromEnv[topLevelKey(cerr.Key)]; ok {
+			cerr.Key = env
+		}
+	}
+	return cfg, err
+}
+
+// topLevelKey returns the first segment of a key such as "sinks[1].format".
+func topLevelKey(key string) string {
+	if i := strings.IndexAny(key, ".["); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
+func readConfigFile(path string) (*fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	fc := &fileConfig{}
+	if err := dec.Decode(fc); err != nil {
+		return nil, &ConfigError{Key: path, Err: err}
+	}
+	return fc, nil
+}
+
+// build converts and validates a file configuration, opening its outputs.
+func (fc *fileConfig) build() (cfg Config, err error) {
+	var opened []io.Closer
+	defer func() {
+		if err != nil {
+			for _, c := range opened {
+				_ = c.Close()
+			}
+		}
+	}()
+	open := func(key, output string) (io.Writer, error) {
+		w, err := openOutput(output)
+		if err != nil {
+			return nil, &ConfigError{Key: key, Err: err}
+		}
+		if c, ok := w.(io.Closer); ok && w != os.Stdout && w != os.Stderr {
+			opened = append(opened, c)
+		}
+		return w, nil
+	}
+
+	cfg = Config{
+		Format:          fc.Format,
+		MinimumLogLevel: fc.Level,
+		LoggerLevels:    fc.LoggerLevels,
+		Async:           fc.Async.toConfig(),
+		GCPProjectID:    fc.GCPProjectID,
+		HandleSignals:   fc.Signals,
+		BaggageKeys:     fc.BaggageKeys,
+	}
+
+	if fc.Output != "" {
+		if cfg.Writer, err = open("output", fc.Output); err != nil {
+			return Config{}, err
+		}
+	}
+	for i, sink := range fc.Sinks {
+		sc := SinkConfig{
+			Format:          sink.Format,
+			MinimumLogLevel: sink.Level,
+			EventTypes:      sink.EventTypes,
+			Async:           sink.Async.toConfig(),
+		}
+		if sink.Output != "" {
+			if sc.Writer, err = open(fmt.Sprintf("sinks[%d].output", i), sink.Output); err != nil {
+				return Config{}, err
+			}
+		}
+		cfg.Sinks = append(cfg.Sinks, sc)
+	}
+
+	if err = cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func (a *fileAsyncConfig) toConfig() *AsyncConfig {
+	if a == nil {
+		return nil
+	}
+	return &AsyncConfig{
+		QueueSize:      a.QueueSize,
+		OverflowPolicy: a.OverflowPolicy,
+		DropBelowLevel: a.DropBelowLevel,
+	}
+}
+
+// Validate checks every value of the configuration and reports the first invalid
+// one. Zero values are valid: New replaces them by their defaults.
+//
+// Returns:
+//   - error: a *ConfigError naming the offending key with its JSON configuration name.
+func (c Config) Validate() error {
+	if err := validateFormat("format", c.Format); err != nil {
+		return err
+	}
+	if err := validateOptionalLevel("level", c.MinimumLogLevel); err != nil {
+		return err
+	}
+
+	patterns := make([]string, 0, len(c.LoggerLevels))
+	for pattern := range c.LoggerLevels {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if err := validateLevel(c.LoggerLevels[pattern]); err != nil {
+			return &ConfigError{Key: fmt.Sprintf("logger_levels[%q]", pattern), Err: err}
+		}
+	}
+
+	if err := c.Async.validate("async"); err != nil {
+		return err
+	}
+	for i, sink := range c.Sinks {
+		key := fmt.Sprintf("sinks[%d]", i)
+		if err := validateFormat(key+".format", sink.Format); err != nil {
+			return err
+		}
+		if err := validateOptionalLevel(key+".level", sink.MinimumLogLevel); err != nil {
+			return err
+		}
+		for j, eventType := range sink.EventTypes {
+			if strings.TrimSpace(eventType) == "" {
+				return &ConfigError{Key: fmt.Sprintf("%s.event_types[%d]", key, j), Err: errors.New("empty event type")}
+			}
+		}
+		if err := sink.Async.validate(key + ".async"); err != nil {
+			return err
+		}
+	}
+
+	for i, k := range c.BaggageKeys {
+		if strings.TrimSpace(k) == "" {
+			return &ConfigError{Key: fmt.Sprintf("baggage_keys[%d]", i), Err: errors.New("empty key")}
+		}
+	}
+	return nil
+}
+
+func (a *AsyncConfig) validate(key string) error {
+	if a == nil {
+		return nil
+	}
+	if a.QueueSize < 0 {
+		return &ConfigError{Key: key + ".queue_size", Err: errors.New("must not be negative")}
+	}
+	switch a.OverflowPolicy {
+	case "", OverflowBlock, OverflowDropNewest, OverflowDropOldest, OverflowDropBelowLevel:
+	default:
+		return &ConfigError{Key: key + ".overflow_policy", Err: fmt.Errorf("unknown policy %q", a.OverflowPolicy)}
+	}
+	return validateOptionalLevel(key+".drop_below_level", a.DropBelowLevel)
+}
+
+func validateFormat(key string, format Format) error {
+	switch format {
+	case "", FormatJSON, FormatPretty, FormatLogfmt, FormatOTLPJSON, FormatECS, FormatGCP, FormatEMF:
+		return nil
+	}
+	return &ConfigError{Key: key, Err: fmt.Errorf("unknown format %q", format)}
+}
+
+func validateOptionalLevel(key string, level Level.LogLevel) error {
+	if level == "" {
+		return nil
+	}
+	if err := validateLevel(level); err != nil {
+		return &ConfigError{Key: key, Err: err}
+	}
+	return nil
+}
+
+// OutputOpener opens the writer of an output URL, such as
+// "file:///var/log/app.log". Writers implementing io.Closer are closed by
+// Logger.Close.
+type OutputOpener func(u *url.URL) (io.Writer, error)
+
+// outputOpeners holds the openers registered with RegisterOutput, by URL scheme.
+var outputOpeners = struct {
+	sync.RWMutex
+	byScheme map[string]OutputOpener
+}{byScheme: map[string]OutputOpener{"file": openFileOutput}}
+
+// RegisterOutput makes outputs with the given URL scheme usable in LoadConfig
+// and ConfigFromEnv. Registering a scheme again replaces its opener, including
+// the built-in "file" one, e.g. to open files through the rotating file sink:
+//
+//	chronolog.RegisterOutput("file", func(u *url.URL) (io.Writer, error) {
+//		return file.OpenURL(u)
+//	})
+//
+// Parameters:
+//   - scheme (string): the URL scheme, such as "file".
+//   - open (OutputOpener): the function opening outputs with that scheme.
+func RegisterOutput(scheme string, open OutputOpener) {
+	outputOpeners.Lock()
+	defer outputOpeners.Unlock()
+	outputOpeners.byScheme[strings.ToLower(scheme)] = open
+}
+
+// openOutput opens "stdout", "stderr" or a URL with a registered scheme.
+func openOutput(output string) (io.Writer, error) {
+	switch output {
+	case "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	}
+
+	u, err := url.Parse(output)
+	if err != nil || u.Scheme == "" {
+		return nil, fmt.Errorf("unsupported output %q, want stdout, stderr or a URL such as file:///path", output)
+	}
+
+	outputOpeners.RLock()
+	open, ok := outputOpeners.byScheme[strings.ToLower(u.Scheme)]
+	outputOpeners.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no output registered for scheme %q", u.Scheme)
+	}
+	return open(u)
+}
+
+// openFileOutput opens a file:// URL for appending, creating the file if needed.
+func openFileOutput(u *url.URL) (io.Writer, error) {
+	switch {
+	case u.Host != "" && u.Host != "localhost":
+		return nil, fmt.Errorf("file URL %q must not name a host (use file:///path)", u)
+	case u.Path == "":
+		return nil, fmt.Errorf("file URL %q has no path", u)
+	case u.RawQuery != "":
+		return nil, fmt.Errorf("file URL %q takes no options", u)
+	}
+	f, err := os.OpenFile(u.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// parseLoggerLevels parses "pattern=level" pairs separated by commas.
+func parseLoggerLevels(s string) (map[string]Level.LogLevel, error) {
+	levels := map[string]Level.LogLevel{}
+	for _, pair := range splitList(s) {
+		pattern, level, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not pattern=level", pair)
+		}
+		levels[strings.TrimSpace(pattern)] = Level.LogLevel(strings.TrimSpace(level))
+	}
+	return levels, nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,153 @@
+package chronolog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	chronologctx "github.com/Astronotify/chronolog/ctx"
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "chronolog.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	path := writeConfigFile(t, `{
+		"format": "logfmt",
+		"level": "debug",
+		"output": "file://`+logPath+`",
+		"logger_levels": {"payments/*": "trace"}
+	}`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Format != FormatLogfmt || cfg.MinimumLogLevel != Level.Debug || cfg.LoggerLevels["payments/*"] != Level.Trace {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	l := New(cfg)
+	l.Debug(context.Background(), "loaded")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := string(data); !strings.Contains(out, "message=loaded") {
+		t.Errorf("unexpected file content: %q", out)
+	}
+}
+
+func TestRegisterOutput(t *testing.T) {
+	var out bytes.Buffer
+	RegisterOutput("test", func(u *url.URL) (io.Writer, error) {
+		if u.Host != "buffer" {
+			return nil, errors.New("unknown test output")
+		}
+		return &out, nil
+	})
+	t.Setenv(EnvOutput, "test://buffer")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	New(cfg).Info(context.Background(), "registered")
+	if !strings.Contains(out.String(), `"message":"registered"`) {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
+
+func TestLoadConfigErrorsNameTheKey(t *testing.T) {
+	for content, key := range map[string]string{
+		`{"format": "xml"}`:                                    "format",
+		`{"level": "loud"}`:                                    "level",
+		`{"output": "syslog://localhost"}`:                     "output",
+		`{"output": "file:///tmp/x.log?rotation=weekly"}`:      "output",
+		`{"output": "file://remote/x.log"}`:                    "output",
+		`{"sinks": [{}, {"format": "yaml"}]}`:                  "sinks[1].format",
+		`{"sinks": [{"async": {"overflow_policy": "spill"}}]}`: "sinks[0].async.overflow_policy",
+		`{"logger_levels": {"db": "chatty"}}`:                  `logger_levels["db"]`,
+		`{"baggage_keys": ["tenant", ""]}`:                     "baggage_keys[1]",
+	} {
+		_, err := LoadConfig(writeConfigFile(t, content))
+		var cerr *ConfigError
+		if !errors.As(err, &cerr) || cerr.Key != key {
+			t.Errorf("%s: err = %v, want key %s", content, err, key)
+		}
+	}
+
+	_, err := LoadConfig(writeConfigFile(t, `{"formt": "json"}`))
+	if err == nil || !strings.Contains(err.Error(), `unknown field "formt"`) {
+		t.Errorf("unknown key: err = %v", err)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvConfig, writeConfigFile(t, `{"format": "ecs", "level": "warn"}`))
+	t.Setenv(EnvLevel, "debug")
+	t.Setenv(EnvOutput, "stderr")
+	t.Setenv(EnvLoggerLevels, "payments/*=trace, db=error")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Format != FormatECS || cfg.MinimumLogLevel != Level.Debug || cfg.Writer != os.Stderr {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.LoggerLevels["db"] != Level.Error || cfg.LoggerLevels["payments/*"] != Level.Trace {
+		t.Errorf("LoggerLevels = %v", cfg.LoggerLevels)
+	}
+
+	t.Setenv(EnvLevel, "verbose")
+	_, err = ConfigFromEnv()
+	var cerr *ConfigError
+	if !errors.As(err, &cerr) || cerr.Key != EnvLevel {
+		t.Errorf("err = %v, want key %s", err, EnvLevel)
+	}
+}
+
+func TestBaggageAllowlist(t *testing.T) {
+	t.Setenv(EnvBaggageKeys, "tenant, session")
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	cfg.Writer = &buf
+	l := New(cfg)
+
+	ctx, err := chronologctx.FromBaggage(context.Background(), "tenant=acme%20corp,session=s-1,card=4111")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info(ctx, "with baggage")
+	l.Info(context.Background(), "without baggage")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"baggage":{"session":"s-1","tenant":"acme corp"}`) {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+	if strings.Contains(buf.String(), "4111") || strings.Contains(lines[1], `"baggage":`) {
+		t.Errorf("baggage not allowlisted: %s", buf.String())
+	}
+}
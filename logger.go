@@ -0,0 +1,399 @@
+package chronolog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/Astronotify/chronolog/entries"
+	"github.com/Astronotify/chronolog/internal"
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+// terminalFlushTimeout bounds how long Fatal and Panic wait for queued entries.
+const terminalFlushTimeout = 5 * time.Second
+
+// Logger is an independent chronolog pipeline with its own writer, format and
+// minimum log level.
+//
+// A Logger is safe for concurrent use. Libraries that need a configuration
+// isolated from the rest of the binary should build their own instance with New
+// instead of calling Setup, which replaces the package-level default.
+type Logger struct {
+	handler slog.Handler
+	exit    func(code int)
+
+	// name is set on loggers derived with Named and selects per-name minimum levels.
+	name string
+	// followsDefault is set on loggers created by the package-level Named, which
+	// resolve the default logger on every call instead of copying it (see base).
+	followsDefault bool
+	// levels is shared by the logger and every logger derived from it.
+	levels *levelState
+
+	baggage *baggageFilter
+
+	// asyncs are the background queues, outermost first, drained by Flush and Close.
+	asyncs []*internal.AsyncHandler
+	// writers are the configured destinations, flushed and closed by Flush and Close.
+	writers []io.Writer
+	// signals is the listener enabled by Config.HandleSignals, stopped by Close.
+	signals *signalListener
+}
+
+// New creates a Logger from the given configuration.
+//
+// Zero-valued fields in cfg are replaced by their defaults: os.Stdout as the
+// writer, FormatJSON as the format and Level.Info as the minimum log level.
+// When cfg.Sinks is non-empty, entries are fanned out to every sink and
+// cfg.Writer and cfg.Format are ignored.
+//
+// Parameters:
+//   - cfg (Config): the configuration of the new logger.
+//
+// Returns:
+//   - *Logger: a ready-to-use logger, independent from the package-level default.
+func New(cfg Config) *Logger {
+	cfg.applyDefaults()
+
+	l := &Logger{
+		exit:    cfg.ExitFunc,
+		levels:  newLevelState(cfg.MinimumLogLevel, cfg.LoggerLevels),
+		baggage: newBaggageFilter(cfg.BaggageKeys),
+	}
+
+	var handler slog.Handler
+	if len(cfg.Sinks) == 0 {
+		handler = l.newSink(&cfg, cfg.Writer, cfg.Format, nil)
+	} else {
+		handlers := make([]slog.Handler, 0, len(cfg.Sinks))
+		for _, sink := range cfg.Sinks {
+			handlers = append(handlers, newSinkHandler(
+				l.newSink(&cfg, sink.Writer, sink.Format, sink.Async),
+				sink.MinimumLogLevel,
+				sink.EventTypes,
+			))
+		}
+		handler = internal.NewMultiHandler(handlers...)
+	}
+
+	if cfg.Async != nil {
+		async := newAsyncHandler(handler, cfg.Async)
+		l.asyncs = append([]*internal.AsyncHandler{async}, l.asyncs...)
+		handler = async
+	}
+
+	l.handler = handler
+	if cfg.HandleSignals {
+		l.startSignals()
+	}
+	return l
+}
+
+// newSink builds the handler of one destination and registers its writer and
+// queue so that Flush and Close reach them.
+func (l *Logger) newSink(cfg *Config, w io.Writer, format Format, async *AsyncConfig) slog.Handler {
+	l.addWriter(w)
+
+	handler := newFormatHandler(w, format, cfg)
+	if async != nil {
+		a := newAsyncHandler(handler, async)
+		l.asyncs = append(l.asyncs, a)
+		handler = a
+	}
+	return handler
+}
+
+// addWriter registers w once, even if several sinks share it.
+func (l *Logger) addWriter(w io.Writer) {
+	if reflect.TypeOf(w).Comparable() {
+		for _, existing := range l.writers {
+			if reflect.TypeOf(existing).Comparable() && existing == w {
+				return
+			}
+		}
+	}
+	l.writers = append(l.writers, w)
+}
+
+func newAsyncHandler(next slog.Handler, cfg *AsyncConfig) *internal.AsyncHandler {
+	return internal.NewAsyncHandler(
+		next,
+		cfg.QueueSize,
+		mapOverflowPolicy(cfg.OverflowPolicy),
+		mapLogLevel(cfg.DropBelowLevel),
+	)
+}
+
+// newWithHandler creates a Logger that dispatches to an arbitrary slog.Handler.
+func newWithHandler(handler slog.Handler, minimumLogLevel Level.LogLevel) *Logger {
+	return &Logger{
+		handler: handler,
+		exit:    os.Exit,
+		levels:  newLevelState(minimumLogLevel, nil),
+	}
+}
+
+// newFallbackLogger creates the basic text logger used when Setup was never called.
+func newFallbackLogger() *Logger {
+	return newWithHandler(slog.NewTextHandler(os.Stdout, nil), Level.Info)
+}
+
+// Flush blocks until every entry logged before the call has been written, or until
+// ctx is done. Writers exposing a Flush(context.Context) error or Flush() error
+// method are flushed afterwards, within the same deadline.
+//
+// For synchronous loggers Flush only flushes the writers.
+//
+// Parameters:
+//   - ctx (context.Context): bounds how long Flush may wait for the queue to drain
+//     and the writers to flush.
+//
+// Returns:
+//   - error: ctx.Err() if the deadline expired first, or the errors returned by the writers.
+func (l *Logger) Flush(ctx context.Context) error {
+	l = l.base()
+	for _, async := range l.asyncs {
+		if err := async.Flush(ctx); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	for _, w := range l.writers {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		errs = append(errs, flushWriter(ctx, w))
+	}
+	return errors.Join(errs...)
+}
+
+// flushWriter flushes w if it exposes a Flush method. A Flush() error method,
+// which cannot be canceled, runs in its own goroutine and is abandoned when ctx
+// is done, so that a stuck writer cannot block the caller past its deadline.
+func flushWriter(ctx context.Context, w io.Writer) error {
+	switch f := w.(type) {
+	case interface{ Flush(context.Context) error }:
+		return f.Flush(ctx)
+	case interface{ Flush() error }:
+		done := make(chan error, 1)
+		go func() { done <- f.Flush() }()
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Close unregisters the signals enabled by Config.HandleSignals, drains the
+// asynchronous queues, stops their workers and closes every writer implementing
+// io.Closer (os.Stdout and os.Stderr are never closed).
+//
+// Entries logged after Close are discarded and counted by DroppedEntries.
+//
+// Returns:
+//   - error: the errors returned while flushing or closing the writers, if any.
+func (l *Logger) Close() error {
+	l = l.base()
+	l.signals.stop()
+
+	for _, async := range l.asyncs {
+		async.Close()
+	}
+
+	var errs []error
+	for _, w := range l.writers {
+		errs = append(errs, flushWriter(context.Background(), w))
+		if c, ok := w.(io.Closer); ok && w != os.Stdout && w != os.Stderr {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DroppedEntries returns how many entries the asynchronous queues discarded because
+// of their overflow policy or because they were logged after Close.
+// It is always zero for synchronous loggers.
+func (l *Logger) DroppedEntries() uint64 {
+	l = l.base()
+	var dropped uint64
+	for _, async := range l.asyncs {
+		dropped += async.Dropped()
+	}
+	return dropped
+}
+
+// Named returns a logger writing to the same sinks whose entries carry the given
+// name in LogEntry.LoggerName and are filtered by the per-name minimum levels
+// (see SetLoggerLevel). Calling Named on a named logger appends to its name with a
+// "." separator.
+//
+// Named loggers share their parent's sinks and level configuration: level changes
+// made through any of them apply to all, and Close closes the shared sinks.
+//
+// Parameters:
+//   - name (string): the logger name, such as "payments.ledger" or "payments/refunds".
+//
+// Returns:
+//   - *Logger: the named logger.
+func (l *Logger) Named(name string) *Logger {
+	named := *l
+	if l.name != "" && name != "" {
+		named.name = l.name + "." + name
+	} else if name != "" {
+		named.name = name
+	}
+	return &named
+}
+
+// base returns the logger holding the sinks and levels l writes through: the
+// current default logger for loggers created by the package-level Named, so that
+// they follow Setup and SetDefault, and l itself otherwise.
+func (l *Logger) base() *Logger {
+	if l.followsDefault {
+		return defaultLogger.Load()
+	}
+	return l
+}
+
+// Name returns the name given with Named, or "" for a root logger.
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// Trace logs a detailed message for low-level debugging purposes. See the package-level Trace.
+func (l *Logger) Trace(ctx context.Context, message string, additionalData ...map[string]any) {
+	l.log(ctx, Level.Trace, message, additionalData...)
+}
+
+// Debug logs a message for detailed debugging information. See the package-level Debug.
+func (l *Logger) Debug(ctx context.Context, message string, additionalData ...map[string]any) {
+	l.log(ctx, Level.Debug, message, additionalData...)
+}
+
+// Info logs an informational message. See the package-level Info.
+func (l *Logger) Info(ctx context.Context, message string, additionalData ...map[string]any) {
+	l.log(ctx, Level.Info, message, additionalData...)
+}
+
+// Warn logs a warning message. See the package-level Warn.
+func (l *Logger) Warn(ctx context.Context, message string, additionalData ...map[string]any) {
+	l.log(ctx, Level.Warn, message, additionalData...)
+}
+
+// Log logs a message at an arbitrary built-in or registered level. See the package-level Log.
+func (l *Logger) Log(ctx context.Context, level Level.LogLevel, message string, additionalData ...map[string]any) {
+	l.log(ctx, level, message, additionalData...)
+}
+
+// Error logs a structured error message. See the package-level Error.
+func (l *Logger) Error(ctx context.Context, err error, additionalData ...map[string]any) {
+	entry := entries.NewErrorLogEntry(
+		ctx,
+		err,
+		internal.MergeAdditionalData(additionalData...),
+	)
+	entry.LoggerName = l.name
+
+	l.write(ctx, entry)
+}
+
+// Panic logs err at Level.Panic, flushes the logger and panics with err.
+// See the package-level Panic.
+func (l *Logger) Panic(ctx context.Context, err error, additionalData ...map[string]any) {
+	l.terminal(ctx, Level.Panic, err, additionalData...)
+	panic(err)
+}
+
+// Fatal logs err at Level.Fatal, flushes the logger and exits with status 1.
+// See the package-level Fatal.
+func (l *Logger) Fatal(ctx context.Context, err error, additionalData ...map[string]any) {
+	l.terminal(ctx, Level.Fatal, err, additionalData...)
+	l.base().exit(1)
+}
+
+// terminal logs an error entry at a terminal level and waits, at most
+// terminalFlushTimeout, for every sink to write it.
+func (l *Logger) terminal(ctx context.Context, level Level.LogLevel, err error, additionalData ...map[string]any) {
+	entry := entries.NewErrorLogEntry(
+		ctx,
+		err,
+		internal.MergeAdditionalData(additionalData...),
+	)
+	entry.Level = level
+	entry.LoggerName = l.name
+
+	l.write(ctx, entry)
+
+	// the caller's context may already be canceled, which is often why it is failing
+	flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), terminalFlushTimeout)
+	defer cancel()
+	_ = l.Flush(flushCtx)
+}
+
+// Entry logs a fully preconstructed log entry. See the package-level Entry.
+func (l *Logger) Entry(ctx context.Context, entry any) {
+	l.write(ctx, entry)
+}
+
+// log builds a plain LogEntry with the given level and emits it.
+func (l *Logger) log(ctx context.Context, level Level.LogLevel, message string, additionalData ...map[string]any) {
+	entry := entries.NewLogEntry(
+		ctx,
+		level,
+		message,
+		internal.MergeAdditionalData(additionalData...),
+	)
+	entry.LoggerName = l.name
+
+	l.write(ctx, entry)
+}
+
+// write is a low-level utility that emits the final log event to the logger backend.
+//
+// This internal function abstracts the actual call to the logging system (e.g., slog, zap, zerolog).
+// It is not meant to be used directly. Instead, prefer higher-level helpers like Info, Warn, or Error.
+//
+// Parameters:
+//   - ctx (context.Context): The context for metadata propagation.
+//   - entry (any): The structured log event to be emitted.
+//
+// Returns:
+//   - None. Side-effect: sends the log entry to the logger.
+func (l *Logger) write(ctx context.Context, entry any) {
+	name := extractLoggerName(entry)
+	if name == "" && l.name != "" {
+		// prebuilt entries inherit the name of the logger they are written to
+		name = l.name
+		entry = updateLogEntry(entry, func(e *entries.LogEntry) { e.LoggerName = name })
+	}
+
+	level := extractLogLevel(entry)
+	if !l.shouldLog(level, name) {
+		return
+	}
+	base := l.base()
+	if base.baggage != nil {
+		entry = base.baggage.apply(ctx, entry)
+	}
+	slogLevel := mapLogLevel(level)
+	if !base.handler.Enabled(ctx, slogLevel) {
+		return
+	}
+
+	record := slog.NewRecord(time.Now(), slogLevel, "log", internal.CallerPC(1))
+	record.AddAttrs(slog.Any("event", entry))
+	_ = base.handler.Handle(ctx, record)
+}
+
+func (l *Logger) shouldLog(level Level.LogLevel, name string) bool {
+	return levelEnabled(level, l.base().levels.levelFor(name))
+}
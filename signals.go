@@ -0,0 +1,139 @@
+package chronolog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/Astronotify/chronolog/entries"
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+// signalListener runs the goroutine reacting to the signals enabled by
+// Config.HandleSignals.
+type signalListener struct {
+	signals chan os.Signal
+	done    chan struct{}
+	stopped sync.WaitGroup
+	once    sync.Once
+}
+
+// startSignals registers the level and reopen signals for l. It does nothing on
+// platforms without SIGUSR1 and SIGUSR2.
+//
+// The listener is the only SIGHUP subscriber: writers that registered it on their
+// own, such as a file sink with ReopenOnSIGHUP, have that registration stopped so
+// that each SIGHUP reopens them once.
+func (l *Logger) startSignals() {
+	if len(handledSignals) == 0 {
+		return
+	}
+
+	for _, w := range l.writers {
+		if r, ok := w.(interface{ StopSignals() }); ok {
+			r.StopSignals()
+		}
+	}
+
+	s := &signalListener{
+		signals: make(chan os.Signal, 1),
+		done:    make(chan struct{}),
+	}
+	signal.Notify(s.signals, handledSignals...)
+
+	s.stopped.Add(1)
+	go func() {
+		defer s.stopped.Done()
+		for {
+			select {
+			case sig := <-s.signals:
+				l.handleSignal(sig)
+			case <-s.done:
+				return
+			}
+		}
+	}()
+	l.signals = s
+}
+
+// stop unregisters the signals and waits for the listener to exit. It is safe
+// to call several times.
+func (s *signalListener) stop() {
+	if s == nil {
+		return
+	}
+	s.once.Do(func() {
+		signal.Stop(s.signals)
+		close(s.done)
+		s.stopped.Wait()
+	})
+}
+
+func (l *Logger) handleSignal(sig os.Signal) {
+	ctx := context.Background()
+
+	step, reopen := signalAction(sig)
+	if reopen {
+		l.reopenWriters(ctx)
+		return
+	}
+
+	previous := l.MinimumLevel()
+	next := stepLevel(previous, step)
+	if next == previous {
+		return
+	}
+	_ = l.SetMinimumLevel(next)
+
+	entry := entries.NewLevelChangeLogEntry(ctx, "", previous, next, "signal", map[string]any{"signal": sig.String()})
+	entry.Level = Level.Info
+	if !levelEnabled(entry.Level, next) {
+		// announce the change even when the new threshold is above info
+		entry.Level = next
+	}
+	l.Entry(ctx, entry)
+}
+
+// reopenWriters reopens every writer exposing a Reopen() error method, such as
+// the file sink, typically after logrotate moved the files away.
+func (l *Logger) reopenWriters(ctx context.Context) {
+	for _, w := range l.writers {
+		r, ok := w.(interface{ Reopen() error })
+		if !ok {
+			continue
+		}
+		if err := r.Reopen(); err != nil {
+			l.Error(ctx, fmt.Errorf("reopen log writer: %w", err))
+		}
+	}
+}
+
+// stepLevel returns the built-in level adjacent to current in LogLevelPriority:
+// the nearest more verbose one for a negative step, the nearest less verbose one
+// otherwise. Stepping never goes past Level.Error, since a panic or fatal minimum
+// would hide every ordinary entry. Custom levels step to the nearest built-in
+// level, and the level is unchanged at either end of the scale or when current
+// is unknown.
+func stepLevel(current Level.LogLevel, step int) Level.LogLevel {
+	priority, ok := Level.Priority(current)
+	if !ok {
+		return current
+	}
+	highest, _ := Level.Priority(Level.Error)
+
+	next, nextPriority, found := current, 0, false
+	for level := range Level.LogLevelPriority {
+		p, _ := Level.Priority(level)
+		if p > highest {
+			continue
+		}
+		below := step < 0 && p < priority && (!found || p > nextPriority)
+		above := step > 0 && p > priority && (!found || p < nextPriority)
+		if below || above {
+			next, nextPriority, found = level, p, true
+		}
+	}
+	return next
+}
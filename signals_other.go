@@ -0,0 +1,12 @@
+//go:build !unix
+
+package chronolog
+
+import "os"
+
+// handledSignals is empty: SIGUSR1 and SIGUSR2 do not exist on this platform.
+var handledSignals []os.Signal
+
+func signalAction(os.Signal) (step int, reopen bool) {
+	return 0, false
+}
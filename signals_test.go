@@ -0,0 +1,166 @@
+//go:build unix
+
+package chronolog
+
+import (
+	"bytes"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+type reopenWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	reopens int
+}
+
+func (w *reopenWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *reopenWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.reopens++
+	return nil
+}
+
+func (w *reopenWriter) snapshot() (string, int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String(), w.reopens
+}
+
+// hupWriter reopens itself on SIGHUP, like a file sink with ReopenOnSIGHUP, until
+// StopSignals is called.
+type hupWriter struct {
+	reopenWriter
+	signals chan os.Signal
+	done    chan struct{}
+}
+
+func newHupWriter() *hupWriter {
+	w := &hupWriter{signals: make(chan os.Signal, 1), done: make(chan struct{})}
+	signal.Notify(w.signals, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-w.signals:
+				_ = w.Reopen()
+			case <-w.done:
+				return
+			}
+		}
+	}()
+	return w
+}
+
+func (w *hupWriter) StopSignals() {
+	signal.Stop(w.signals)
+	close(w.done)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSignalsStepLevelAndReopen(t *testing.T) {
+	w := &reopenWriter{}
+	l := New(Config{Writer: w, MinimumLogLevel: Level.Info, HandleSignals: true})
+	defer l.Close()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return l.MinimumLevel() == Level.Debug })
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return l.MinimumLevel() == Level.Info })
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { _, n := w.snapshot(); return n == 1 })
+
+	out, _ := w.snapshot()
+	if !strings.Contains(out, `"previous_level":"info","new_level":"debug","source":"signal"`) ||
+		!strings.Contains(out, `"signal":"user defined signal 1"`) {
+		t.Errorf("level change not announced:\n%s", out)
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	l.signals.stop() // a second stop is a no-op
+}
+
+func TestSignalsReopenOncePerSIGHUP(t *testing.T) {
+	w := newHupWriter()
+	l := New(Config{Writer: w, HandleSignals: true})
+	defer l.Close()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { _, n := w.snapshot(); return n >= 1 })
+	time.Sleep(50 * time.Millisecond)
+
+	if _, n := w.snapshot(); n != 1 {
+		t.Errorf("reopens = %d, want 1", n)
+	}
+}
+
+func TestStepLevel(t *testing.T) {
+	for _, tc := range []struct {
+		from Level.LogLevel
+		step int
+		want Level.LogLevel
+	}{
+		{Level.Info, -1, Level.Debug},
+		{Level.Info, 1, Level.Warn},
+		{Level.Trace, -1, Level.Trace},
+		{Level.Warn, 1, Level.Error},
+		{Level.Error, 1, Level.Error},
+		{Level.Fatal, 1, Level.Fatal},
+		{Level.Fatal, -1, Level.Error},
+		{"unknown", 1, "unknown"},
+	} {
+		if got := stepLevel(tc.from, tc.step); got != tc.want {
+			t.Errorf("stepLevel(%s, %d) = %s, want %s", tc.from, tc.step, got, tc.want)
+		}
+	}
+}
+
+func TestSetupStopsReplacedListener(t *testing.T) {
+	previous := Default()
+	defer SetDefault(previous)
+
+	Setup(Config{Writer: &reopenWriter{}, HandleSignals: true})
+	first := Default()
+	Setup(Config{Writer: &reopenWriter{}, HandleSignals: true})
+	defer Default().Close()
+
+	select {
+	case <-first.signals.done:
+	default:
+		t.Error("the replaced default logger still handles signals")
+	}
+}
@@ -0,0 +1,24 @@
+//go:build unix
+
+package chronolog
+
+import (
+	"os"
+	"syscall"
+)
+
+// handledSignals are the signals registered when Config.HandleSignals is set.
+var handledSignals = []os.Signal{syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGHUP}
+
+// signalAction returns the level step requested by sig, or whether it asks for
+// the writers to be reopened.
+func signalAction(sig os.Signal) (step int, reopen bool) {
+	switch sig {
+	case syscall.SIGUSR1:
+		return -1, false
+	case syscall.SIGUSR2:
+		return 1, false
+	default:
+		return 0, true
+	}
+}
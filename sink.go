@@ -0,0 +1,133 @@
+package chronolog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/Astronotify/chronolog/internal"
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+// SinkConfig describes one destination of a fan-out configuration.
+//
+// Fields:
+//
+//   - Writer: destination of the encoded entries. Defaults to os.Stdout.
+//   - Format: encoding used for this sink. Defaults to FormatJSON.
+//   - MinimumLogLevel: entries below this level are not sent to this sink.
+//     Defaults to Config.MinimumLogLevel, or Level.Info when that is empty too.
+//   - EventTypes: when non-empty, only entries whose event type (e.g. "ErrorLogEntry")
+//     is listed are sent to this sink.
+//   - Async: optional dedicated queue for this sink, so a slow writer does not
+//     delay the other sinks.
+type SinkConfig struct {
+	Writer          io.Writer
+	Format          Format
+	MinimumLogLevel Level.LogLevel
+	EventTypes      []string
+	Async           *AsyncConfig
+}
+
+// sinkHandler filters records by level and event type before handing them to a
+// sink's format handler.
+type sinkHandler struct {
+	next            slog.Handler
+	minimumLogLevel Level.LogLevel
+	eventTypes      map[string]struct{}
+}
+
+func newSinkHandler(next slog.Handler, minimumLogLevel Level.LogLevel, eventTypes []string) *sinkHandler {
+	h := &sinkHandler{
+		next:            next,
+		minimumLogLevel: minimumLogLevel,
+	}
+	if len(eventTypes) > 0 {
+		h.eventTypes = make(map[string]struct{}, len(eventTypes))
+		for _, t := range eventTypes {
+			h.eventTypes[t] = struct{}{}
+		}
+	}
+	return h
+}
+
+func (h *sinkHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *sinkHandler) Handle(ctx context.Context, record slog.Record) error {
+	event := internal.ExtractEvent(record)
+	if event == nil {
+		return nil
+	}
+	if !levelEnabled(extractLogLevel(event), h.minimumLogLevel) {
+		return nil
+	}
+	if h.eventTypes != nil {
+		if _, ok := h.eventTypes[extractEventType(event)]; !ok {
+			return nil
+		}
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *sinkHandler) WithAttrs(_ []slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *sinkHandler) WithGroup(_ string) slog.Handler {
+	return h
+}
+
+// newFormatHandler creates the handler encoding entries in the given format.
+// Format-specific settings are read from cfg.
+func newFormatHandler(w io.Writer, format Format, cfg *Config) slog.Handler {
+	switch format {
+	case FormatPretty:
+		return internal.NewPrettyConsoleHandler(w)
+	case FormatJSON:
+		return internal.NewJSONOnlyHandler(w)
+	case FormatLogfmt:
+		return internal.NewLogfmtHandler(w)
+	case FormatOTLPJSON:
+		return internal.NewOTLPJSONHandler(w)
+	case FormatECS:
+		return internal.NewECSHandler(w)
+	case FormatGCP:
+		return internal.NewGCPHandler(w, cfg.GCPProjectID)
+	case FormatEMF:
+		return newEMFHandler(w, cfg.EMF)
+	default:
+		return internal.NewJSONOnlyHandler(w)
+	}
+}
+
+func newEMFHandler(w io.Writer, cfg *EMFConfig) slog.Handler {
+	namespace := "Chronolog"
+	metrics := DefaultEMFMetrics()
+	if cfg != nil {
+		if cfg.Namespace != "" {
+			namespace = cfg.Namespace
+		}
+		if cfg.Metrics != nil {
+			metrics = cfg.Metrics
+		}
+	}
+
+	rules := make(map[string]internal.EMFRule, len(metrics))
+	for eventType, metric := range metrics {
+		dimensions := make([]internal.EMFDimension, len(metric.Dimensions))
+		for i, d := range metric.Dimensions {
+			dimensions[i] = internal.EMFDimension{Name: d.Name, Field: d.Field}
+		}
+		rules[eventType] = internal.EMFRule{Dimensions: dimensions}
+	}
+	return internal.NewEMFHandler(w, namespace, rules)
+}
+
+func extractEventType(entry any) string {
+	if e, ok := entry.(interface{ GetEventType() string }); ok {
+		return e.GetEventType()
+	}
+	return internal.GetStructName(entry)
+}
@@ -0,0 +1,79 @@
+package chronolog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(_ []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestSinksFilterByLevelAndEventType(t *testing.T) {
+	var console, file, alerts bytes.Buffer
+	l := New(Config{Sinks: []SinkConfig{
+		{Writer: &console, Format: FormatPretty, MinimumLogLevel: Level.Debug},
+		{Writer: &file, Format: FormatJSON, MinimumLogLevel: Level.Info},
+		{Writer: &alerts, Format: FormatJSON, EventTypes: []string{"ErrorLogEntry"}},
+	}})
+
+	ctx := context.Background()
+	l.Debug(ctx, "debug message")
+	l.Info(ctx, "info message")
+	l.Error(ctx, errors.New("boom"))
+
+	for _, msg := range []string{"debug message", "info message", "boom"} {
+		if !strings.Contains(console.String(), msg) {
+			t.Errorf("console: expected %q in output %q", msg, console.String())
+		}
+	}
+	if got := strings.Count(file.String(), "\n"); got != 2 {
+		t.Errorf("file: expected 2 lines, got %d: %q", got, file.String())
+	}
+	if strings.Contains(file.String(), "debug message") {
+		t.Errorf("file: debug entry should be filtered")
+	}
+	if got := strings.Count(alerts.String(), "\n"); got != 1 || !strings.Contains(alerts.String(), "boom") {
+		t.Errorf("alerts: expected only the error entry, got %q", alerts.String())
+	}
+}
+
+func TestFailingSinkDoesNotBlockOthers(t *testing.T) {
+	var healthy bytes.Buffer
+	l := New(Config{Sinks: []SinkConfig{
+		{Writer: failingWriter{}},
+		{Writer: &healthy},
+	}})
+
+	l.Info(context.Background(), "still delivered")
+
+	if !strings.Contains(healthy.String(), "still delivered") {
+		t.Errorf("expected healthy sink to receive the entry, got %q", healthy.String())
+	}
+}
+
+func TestSinkAsyncIsFlushed(t *testing.T) {
+	w := newGatedWriter()
+	close(w.gate)
+	l := New(Config{Sinks: []SinkConfig{
+		{Writer: w, Async: &AsyncConfig{QueueSize: 2}},
+	}})
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		l.Info(ctx, "queued")
+	}
+	if err := l.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if got := strings.Count(w.String(), "\n"); got != 5 {
+		t.Errorf("expected 5 entries, got %d", got)
+	}
+}
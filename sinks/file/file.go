@@ -0,0 +1,477 @@
+// Package file provides a rotating file writer that can be used as the Writer of
+// a chronolog configuration.
+//
+// Segments are rotated by size and/or on hourly or daily boundaries. Rotated
+// segments are renamed with a timestamp suffix, optionally gzip-compressed, and
+// pruned according to the configured retention.
+package file
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rotation selects the time-based rollover policy of a Writer.
+type Rotation string
+
+const (
+	// RotateNever disables time-based rotation. Segments are rotated only by size.
+	RotateNever Rotation = ""
+
+	// RotateHourly starts a new segment at the beginning of every UTC hour.
+	RotateHourly Rotation = "hourly"
+
+	// RotateDaily starts a new segment at midnight UTC.
+	RotateDaily Rotation = "daily"
+)
+
+// backupTimeFormat is the timestamp layout appended to rotated segment names.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+const compressSuffix = ".gz"
+
+// Options configures a rotating Writer.
+//
+// Fields:
+//
+//   - Filename: path of the active log file. Parent directories are created if missing.
+//   - MaxSize: maximum size in bytes of a segment before it is rotated. Zero disables
+//     size-based rotation.
+//   - Rotation: time-based rollover policy (RotateNever, RotateHourly or RotateDaily).
+//   - MaxBackups: maximum number of rotated segments to keep. Zero keeps all of them.
+//   - MaxAge: maximum age of rotated segments. Zero keeps them regardless of age.
+//   - Compress: gzip rotated segments in the background.
+//   - ReopenOnSIGHUP: reopen the active file when the process receives SIGHUP, which
+//     cooperates with external tools such as logrotate that move the file away.
+//     Ignored on platforms without SIGHUP.
+type Options struct {
+	Filename       string
+	MaxSize        int64
+	Rotation       Rotation
+	MaxBackups     int
+	MaxAge         time.Duration
+	Compress       bool
+	ReopenOnSIGHUP bool
+}
+
+// Writer is an io.WriteCloser that writes to a file and rotates it according to
+// its Options.
+//
+// A Writer is safe for concurrent use. When created over an existing file it
+// resumes appending to it, so process restarts continue the current segment.
+type Writer struct {
+	opts Options
+
+	mu          sync.Mutex
+	file        *os.File
+	size        int64
+	periodStart time.Time
+	closed      bool
+
+	now func() time.Time
+
+	millCh   chan struct{}
+	millDone chan struct{}
+
+	signals    chan os.Signal
+	signalDone chan struct{}
+	signalStop sync.Once
+}
+
+// New opens (or creates) the file described by opts and returns a rotating Writer.
+//
+// Parameters:
+//   - opts (Options): the rotation and retention settings.
+//
+// Returns:
+//   - *Writer: the ready-to-use writer.
+//   - error: if the filename is empty, the rotation policy is unknown or the file cannot be opened.
+func New(opts Options) (*Writer, error) {
+	if opts.Filename == "" {
+		return nil, errors.New("file: Filename is required")
+	}
+	switch opts.Rotation {
+	case RotateNever, RotateHourly, RotateDaily:
+	default:
+		return nil, fmt.Errorf("file: unknown rotation %q", opts.Rotation)
+	}
+
+	w := &Writer{
+		opts:     opts,
+		now:      time.Now,
+		millCh:   make(chan struct{}, 1),
+		millDone: make(chan struct{}),
+	}
+
+	if err := w.openExisting(); err != nil {
+		return nil, err
+	}
+
+	go w.millLoop()
+
+	if opts.ReopenOnSIGHUP && len(reopenSignals) > 0 {
+		w.signals = make(chan os.Signal, 1)
+		w.signalDone = make(chan struct{})
+		signal.Notify(w.signals, reopenSignals...)
+		go w.signalLoop()
+	}
+
+	return w, nil
+}
+
+// Write appends p to the active segment, rotating first when the segment would
+// exceed MaxSize or when a rotation boundary has been crossed.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	if w.needsRotation(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate forces the active segment to be rotated immediately.
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return os.ErrClosed
+	}
+	return w.rotate()
+}
+
+// Reopen closes and reopens the active file at its configured path.
+//
+// It is meant to be called after an external tool renamed or removed the file,
+// so that subsequent writes go to a fresh file at the original location.
+func (w *Writer) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return os.ErrClosed
+	}
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	return w.openExisting()
+}
+
+// Sync commits the active segment to stable storage.
+func (w *Writer) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return os.ErrClosed
+	}
+	return w.file.Sync()
+}
+
+// Close stops the signal listener, waits for pending compression and retention
+// work, and closes the active file. Further writes return os.ErrClosed.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	err := w.file.Close()
+	close(w.millCh)
+	w.mu.Unlock()
+
+	w.StopSignals()
+	<-w.millDone
+	return err
+}
+
+// StopSignals unregisters the SIGHUP listener enabled by Options.ReopenOnSIGHUP,
+// leaving Reopen to the caller. A logger handling SIGHUP itself calls it so that
+// each signal reopens the file once. It is safe to call several times.
+func (w *Writer) StopSignals() {
+	if w.signals == nil {
+		return
+	}
+	w.signalStop.Do(func() {
+		signal.Stop(w.signals)
+		close(w.signalDone)
+	})
+}
+
+// openExisting opens the configured file for appending and restores the size and
+// rotation period of the segment it contains. Callers must hold w.mu.
+func (w *Writer) openExisting() error {
+	if err := os.MkdirAll(filepath.Dir(w.opts.Filename), 0o755); err != nil {
+		return fmt.Errorf("file: create directory: %w", err)
+	}
+
+	f, err := os.OpenFile(w.opts.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("file: open %s: %w", w.opts.Filename, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("file: stat %s: %w", w.opts.Filename, err)
+	}
+
+	w.file = f
+	w.size = info.Size()
+	w.periodStart = w.period(w.now())
+	if w.size > 0 {
+		w.periodStart = w.period(info.ModTime())
+	}
+	return nil
+}
+
+// needsRotation reports whether writing n more bytes requires a new segment.
+func (w *Writer) needsRotation(n int64) bool {
+	if w.opts.Rotation != RotateNever && w.period(w.now()).After(w.periodStart) {
+		return true
+	}
+	return w.opts.MaxSize > 0 && w.size > 0 && w.size+n > w.opts.MaxSize
+}
+
+// rotate renames the active file to a timestamped backup, opens a fresh one and
+// schedules compression and retention. Callers must hold w.mu.
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+
+	if w.size > 0 {
+		backup := w.backupName(w.now())
+		if err := os.Rename(w.opts.Filename, backup); err != nil && !os.IsNotExist(err) {
+			// keep writing to the current segment rather than losing entries
+			if reopenErr := w.openExisting(); reopenErr != nil {
+				return errors.Join(err, reopenErr)
+			}
+			return fmt.Errorf("file: rotate %s: %w", w.opts.Filename, err)
+		}
+	}
+
+	f, err := os.OpenFile(w.opts.Filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("file: open %s: %w", w.opts.Filename, err)
+	}
+	w.file = f
+	w.size = 0
+	w.periodStart = w.period(w.now())
+
+	select {
+	case w.millCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// period returns the start of the rotation period containing t.
+func (w *Writer) period(t time.Time) time.Time {
+	t = t.UTC()
+	switch w.opts.Rotation {
+	case RotateHourly:
+		return t.Truncate(time.Hour)
+	case RotateDaily:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Time{}
+	}
+}
+
+// backupName returns the path a segment rotated at t is renamed to.
+func (w *Writer) backupName(t time.Time) string {
+	prefix, ext := w.nameParts()
+	name := prefix + t.UTC().Format(backupTimeFormat) + ext
+	for i := 1; ; i++ {
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			if _, err := os.Stat(name + compressSuffix); os.IsNotExist(err) {
+				return name
+			}
+		}
+		name = fmt.Sprintf("%s%s.%d%s", prefix, t.UTC().Format(backupTimeFormat), i, ext)
+	}
+}
+
+// nameParts splits the configured filename into the backup prefix and extension.
+func (w *Writer) nameParts() (string, string) {
+	ext := filepath.Ext(w.opts.Filename)
+	return strings.TrimSuffix(w.opts.Filename, ext) + "-", ext
+}
+
+func (w *Writer) signalLoop() {
+	for {
+		select {
+		case <-w.signals:
+			_ = w.Reopen()
+		case <-w.signalDone:
+			return
+		}
+	}
+}
+
+func (w *Writer) millLoop() {
+	defer close(w.millDone)
+	for range w.millCh {
+		_ = w.mill()
+	}
+}
+
+// backup describes a rotated segment found on disk.
+type backup struct {
+	path      string
+	timestamp time.Time
+}
+
+// mill compresses rotated segments and removes the ones exceeding the retention.
+func (w *Writer) mill() error {
+	backups, err := w.backups()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	var keep []backup
+	cutoff := w.now().Add(-w.opts.MaxAge)
+	for i, b := range backups {
+		expired := w.opts.MaxAge > 0 && b.timestamp.Before(cutoff)
+		excess := w.opts.MaxBackups > 0 && i >= w.opts.MaxBackups
+		if expired || excess {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		keep = append(keep, b)
+	}
+
+	if w.opts.Compress {
+		for _, b := range keep {
+			if strings.HasSuffix(b.path, compressSuffix) {
+				continue
+			}
+			if err := compress(b.path); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// backups lists rotated segments, newest first.
+func (w *Writer) backups() ([]backup, error) {
+	prefix, ext := w.nameParts()
+	entries, err := os.ReadDir(filepath.Dir(w.opts.Filename))
+	if err != nil {
+		return nil, err
+	}
+
+	base := filepath.Base(prefix)
+	var backups []backup
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		t, ok := parseBackupName(e.Name(), base, ext)
+		if !ok {
+			continue
+		}
+		backups = append(backups, backup{
+			path:      filepath.Join(filepath.Dir(w.opts.Filename), e.Name()),
+			timestamp: t,
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].timestamp.After(backups[j].timestamp)
+	})
+	return backups, nil
+}
+
+// parseBackupName extracts the rotation timestamp from a segment named
+// <base><timestamp>[.N]<ext>[.gz].
+func parseBackupName(name, base, ext string) (time.Time, bool) {
+	rest, ok := strings.CutPrefix(name, base)
+	if !ok || len(rest) < len(backupTimeFormat) {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(backupTimeFormat, rest[:len(backupTimeFormat)])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	rest = strings.TrimSuffix(rest[len(backupTimeFormat):], compressSuffix)
+	rest, ok = strings.CutSuffix(rest, ext)
+	if !ok {
+		return time.Time{}, false
+	}
+	if rest != "" {
+		n, ok := strings.CutPrefix(rest, ".")
+		if !ok || strings.Trim(n, "0123456789") != "" || n == "" {
+			return time.Time{}, false
+		}
+	}
+	return t, true
+}
+
+// compress gzips src into src.gz and removes src once the copy is complete.
+func compress(src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	tmp := src + compressSuffix + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode())
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, src+compressSuffix); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package file
+
+import "os"
+
+// reopenSignals is empty: SIGHUP does not exist on this platform.
+var reopenSignals []os.Signal
@@ -0,0 +1,192 @@
+package file
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestSizeRotationAndMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	w, err := New(Options{Filename: filepath.Join(dir, "app.log"), MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	clock := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	w.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := w.Write([]byte("0123456789")); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	names := listDir(t, dir)
+	if len(names) != 3 {
+		t.Fatalf("expected active file plus 2 backups, got %v", names)
+	}
+}
+
+func TestResumesExistingSegment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("previous\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := New(Options{Filename: path, MaxSize: 100})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := w.Write([]byte("next\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	w.Close()
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "previous\nnext\n" {
+		t.Errorf("expected segment to be resumed, got %q", data)
+	}
+}
+
+func TestTimeRotationWithCompression(t *testing.T) {
+	dir := t.TempDir()
+	w, err := New(Options{Filename: filepath.Join(dir, "app.log"), Rotation: RotateHourly, Compress: true})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	clock := time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)
+	w.now = func() time.Time { return clock }
+	w.periodStart = w.period(clock)
+
+	w.Write([]byte("first hour\n"))
+	clock = clock.Add(time.Hour)
+	w.Write([]byte("second hour\n"))
+	w.Close()
+
+	var gzName string
+	for _, name := range listDir(t, dir) {
+		if strings.HasSuffix(name, ".log.gz") {
+			gzName = name
+		}
+	}
+	if gzName == "" {
+		t.Fatalf("expected a compressed backup, got %v", listDir(t, dir))
+	}
+
+	f, _ := os.Open(filepath.Join(dir, gzName))
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	data, _ := io.ReadAll(gz)
+	if string(data) != "first hour\n" {
+		t.Errorf("unexpected backup content %q", data)
+	}
+}
+
+func TestReopenAfterExternalMove(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	w, err := New(Options{Filename: path})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer w.Close()
+
+	w.Write([]byte("before\n"))
+	os.Rename(path, filepath.Join(dir, "moved.log"))
+	if err := w.Reopen(); err != nil {
+		t.Fatalf("Reopen: %v", err)
+	}
+	w.Write([]byte("after\n"))
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "after\n" {
+		t.Errorf("expected fresh file after reopen, got %q", data)
+	}
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	const writers, lines = 8, 50
+
+	dir := t.TempDir()
+	w, err := New(Options{Filename: filepath.Join(dir, "app.log"), MaxSize: 64})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < lines; j++ {
+				if _, err := fmt.Fprintf(w, "writer-%d line-%02d\n", i, j); err != nil {
+					t.Errorf("Write: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	var total int
+	seen := map[string]bool{}
+	for _, name := range listDir(t, dir) {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		total += len(data)
+		if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+			t.Errorf("%s ends with a partial line", name)
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+			var writer, n int
+			if _, err := fmt.Sscanf(line, "writer-%d line-%02d", &writer, &n); err != nil || len(line) != len("writer-0 line-00") {
+				t.Errorf("%s: incomplete line %q", name, line)
+			}
+			seen[line] = true
+		}
+	}
+
+	if want := writers * lines * len("writer-0 line-00\n"); total != want {
+		t.Errorf("total bytes = %d, want %d", total, want)
+	}
+	if len(seen) != writers*lines {
+		t.Errorf("distinct lines = %d, want %d", len(seen), writers*lines)
+	}
+}
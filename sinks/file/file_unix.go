@@ -0,0 +1,11 @@
+//go:build unix
+
+package file
+
+import (
+	"os"
+	"syscall"
+)
+
+// reopenSignals are the signals registered when Options.ReopenOnSIGHUP is set.
+var reopenSignals = []os.Signal{syscall.SIGHUP}
@@ -0,0 +1,76 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// OpenURL opens the Writer described by a file URL, such as
+// "file:///var/log/app.log?max_size_mb=100&rotation=daily&compress=true".
+//
+// The query string sets the Options: max_size_mb, rotation, max_backups,
+// max_age (a duration such as "168h"), compress and reopen_on_sighup. Pass it to
+// chronolog.RegisterOutput to rotate the file outputs of LoadConfig and
+// ConfigFromEnv.
+//
+// Parameters:
+//   - u (*url.URL): the file URL. It must not name a host other than localhost.
+//
+// Returns:
+//   - *Writer: the ready-to-use writer.
+//   - error: if the URL or one of its options is invalid, or the file cannot be opened.
+func OpenURL(u *url.URL) (*Writer, error) {
+	if u.Scheme != "file" {
+		return nil, fmt.Errorf("file: unsupported URL scheme %q", u.Scheme)
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return nil, fmt.Errorf("file URL %q must not name a host (use file:///path)", u)
+	}
+	if u.Path == "" {
+		return nil, fmt.Errorf("file URL %q has no path", u)
+	}
+
+	opts, err := urlOptions(u.Path, u.Query())
+	if err != nil {
+		return nil, err
+	}
+	return New(opts)
+}
+
+func urlOptions(path string, query url.Values) (Options, error) {
+	opts := Options{Filename: path}
+	for name, values := range query {
+		value := values[len(values)-1]
+		var err error
+		switch name {
+		case "max_size_mb":
+			var mb int64
+			mb, err = strconv.ParseInt(value, 10, 64)
+			opts.MaxSize = mb << 20
+		case "rotation":
+			switch Rotation(value) {
+			case RotateNever, RotateHourly, RotateDaily:
+				opts.Rotation = Rotation(value)
+			default:
+				err = fmt.Errorf("unknown rotation %q", value)
+			}
+		case "max_backups":
+			opts.MaxBackups, err = strconv.Atoi(value)
+		case "max_age":
+			opts.MaxAge, err = time.ParseDuration(value)
+		case "compress":
+			opts.Compress, err = strconv.ParseBool(value)
+		case "reopen_on_sighup":
+			opts.ReopenOnSIGHUP, err = strconv.ParseBool(value)
+		default:
+			err = errors.New("unknown option")
+		}
+		if err != nil {
+			return Options{}, fmt.Errorf("file option %s: %w", name, err)
+		}
+	}
+	return opts, nil
+}
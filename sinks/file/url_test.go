@@ -0,0 +1,44 @@
+package file
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOpenURL(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	u, err := url.Parse("file://" + logPath + "?max_size_mb=10&max_backups=3&max_age=168h&rotation=daily")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := OpenURL(u)
+	if err != nil {
+		t.Fatalf("OpenURL: %v", err)
+	}
+	if w.opts.MaxSize != 10<<20 || w.opts.MaxBackups != 3 || w.opts.MaxAge != 168*time.Hour || w.opts.Rotation != RotateDaily {
+		t.Errorf("unexpected options: %+v", w.opts)
+	}
+	w.Write([]byte("to file\n"))
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if data, _ := os.ReadFile(logPath); string(data) != "to file\n" {
+		t.Errorf("unexpected file content: %q", data)
+	}
+
+	for _, raw := range []string{
+		"file://" + logPath + "?rotation=weekly",
+		"file://" + logPath + "?colour=blue",
+		"file://remote/app.log",
+		"https://example.com/app.log",
+	} {
+		u, _ := url.Parse(raw)
+		if _, err := OpenURL(u); err == nil {
+			t.Errorf("OpenURL(%s): expected an error", raw)
+		}
+	}
+}
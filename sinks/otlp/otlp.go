@@ -0,0 +1,499 @@
+// Package otlp provides a writer that ships chronolog entries to an OpenTelemetry
+// collector over OTLP/HTTP using the JSON encoding, without depending on the
+// OpenTelemetry SDK.
+//
+// The Exporter consumes the lines produced by chronolog.FormatOTLPJSON:
+//
+//	exp, err := otlp.New(otlp.Options{Endpoint: "http://localhost:4318/v1/logs"})
+//	chronolog.Setup(chronolog.Config{Writer: exp, Format: chronolog.FormatOTLPJSON})
+//	defer chronolog.Close() // flushes and stops the exporter
+//
+// Use Shutdown instead of Close to bound how long pending batches may be retried.
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrClosed is returned by Write once the exporter has been closed.
+var ErrClosed = errors.New("otlp: exporter closed")
+
+// Options configures an Exporter.
+//
+// Fields:
+//
+//   - Endpoint: full URL of the OTLP/HTTP logs endpoint (e.g. http://collector:4318/v1/logs).
+//   - Headers: extra HTTP headers sent with every request, such as authentication tokens.
+//   - Client: HTTP client used for requests. Defaults to a client with a 10s timeout.
+//   - MaxBatchSize: maximum number of records per request. Defaults to 512.
+//   - MaxBatchBytes: maximum uncompressed payload size per request. Defaults to 1 MiB.
+//   - FlushInterval: maximum time a record waits before being exported. Defaults to 5s.
+//   - MaxPending: maximum number of records buffered while the collector is unreachable.
+//     The oldest records are dropped beyond it. Defaults to 8192.
+//   - DisableCompression: send payloads uncompressed instead of gzip.
+//   - MaxRetries: number of retries for retryable failures. Defaults to 5; a negative
+//     value disables retries.
+//   - InitialBackoff: delay before the first retry, doubled on each attempt. Defaults to 500ms.
+//   - MaxBackoff: upper bound of the retry delay, including delays requested with
+//     Retry-After. Defaults to 30s.
+//   - OnError: optional callback receiving errors from background exports.
+type Options struct {
+	Endpoint           string
+	Headers            map[string]string
+	Client             *http.Client
+	MaxBatchSize       int
+	MaxBatchBytes      int
+	FlushInterval      time.Duration
+	MaxPending         int
+	DisableCompression bool
+	MaxRetries         int
+	InitialBackoff     time.Duration
+	MaxBackoff         time.Duration
+	OnError            func(error)
+}
+
+// Exporter is an io.Writer that batches OTLP/JSON log lines and posts them to an
+// OTLP/HTTP endpoint from a background goroutine.
+//
+// Batches are sent when they reach MaxBatchSize records or MaxBatchBytes bytes,
+// every FlushInterval, and on Flush and Close. Responses with status 429 or 5xx
+// and network errors are retried with exponential backoff, honoring Retry-After
+// up to MaxBackoff.
+type Exporter struct {
+	opts Options
+
+	mu           sync.Mutex
+	pending      []json.RawMessage
+	pendingBytes int
+	partial      []byte
+	closed       bool
+
+	dropped atomic.Uint64
+
+	// ctx bounds background exports; it is canceled by Shutdown.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	kick     chan struct{}
+	flushReq chan flushRequest
+	done     chan struct{}
+	stopped  chan struct{}
+
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// New validates opts and starts the export loop.
+//
+// Parameters:
+//   - opts (Options): endpoint, batching and retry settings.
+//
+// Returns:
+//   - *Exporter: the running exporter. Call Close to flush and stop it.
+//   - error: if no endpoint was provided.
+func New(opts Options) (*Exporter, error) {
+	if opts.Endpoint == "" {
+		return nil, errors.New("otlp: Endpoint is required")
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if opts.MaxBatchSize <= 0 {
+		opts.MaxBatchSize = 512
+	}
+	if opts.MaxBatchBytes <= 0 {
+		opts.MaxBatchBytes = 1 << 20
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 5 * time.Second
+	}
+	if opts.MaxPending <= 0 {
+		opts.MaxPending = 8192
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	} else if opts.MaxRetries == 0 {
+		opts.MaxRetries = 5
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = 500 * time.Millisecond
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 30 * time.Second
+	}
+
+	e := &Exporter{
+		opts:     opts,
+		kick:     make(chan struct{}, 1),
+		flushReq: make(chan flushRequest),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+		sleep:    sleepContext,
+	}
+	e.ctx, e.cancel = context.WithCancel(context.Background())
+	go e.run()
+	return e, nil
+}
+
+// Write buffers the OTLP/JSON lines contained in p. Each line must be an
+// ExportLogsServiceRequest, as produced by chronolog.FormatOTLPJSON.
+func (e *Exporter) Write(p []byte) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed {
+		return 0, ErrClosed
+	}
+
+	e.partial = append(e.partial, p...)
+	var errs []error
+	for {
+		i := bytes.IndexByte(e.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimSpace(e.partial[:i])
+		e.partial = e.partial[i+1:]
+		if len(line) == 0 {
+			continue
+		}
+		if err := e.appendLine(line); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(e.partial) == 0 {
+		e.partial = nil
+	}
+
+	if len(e.pending) >= e.opts.MaxBatchSize || e.pendingBytes >= e.opts.MaxBatchBytes {
+		select {
+		case e.kick <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), errors.Join(errs...)
+}
+
+// flushRequest asks the export loop to export every buffered record under ctx.
+type flushRequest struct {
+	ctx   context.Context
+	reply chan error
+}
+
+// Flush synchronously exports every buffered record. When ctx is done, retries
+// stop and the records not yet exported stay buffered for the next export.
+//
+// Parameters:
+//   - ctx (context.Context): bounds how long Flush may wait, retries included.
+//
+// Returns:
+//   - error: ctx.Err() if the deadline expired first, or the export errors.
+func (e *Exporter) Flush(ctx context.Context) error {
+	req := flushRequest{ctx: ctx, reply: make(chan error, 1)}
+	select {
+	case e.flushReq <- req:
+	case <-e.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-req.reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown flushes the buffered records and stops the export loop. When ctx is
+// done first, the export in progress is abandoned and the records still buffered
+// are dropped. Further writes return ErrClosed.
+//
+// Parameters:
+//   - ctx (context.Context): bounds how long Shutdown may wait, retries included.
+//
+// Returns:
+//   - error: ctx.Err() if the deadline expired first, or the export errors.
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil
+	}
+	e.closed = true
+	e.mu.Unlock()
+
+	err := e.Flush(ctx)
+	e.cancel()
+	close(e.done)
+	<-e.stopped
+
+	e.mu.Lock()
+	e.dropped.Add(uint64(len(e.pending)))
+	e.pending, e.pendingBytes = nil, 0
+	e.mu.Unlock()
+	return err
+}
+
+// Close flushes the buffered records and stops the export loop, without a
+// deadline. See Shutdown.
+func (e *Exporter) Close() error {
+	return e.Shutdown(context.Background())
+}
+
+// Dropped returns the number of records discarded because the buffer was full or
+// because the collector rejected them permanently or retries were exhausted.
+func (e *Exporter) Dropped() uint64 {
+	return e.dropped.Load()
+}
+
+// appendLine extracts the resourceLogs of one request line. Callers must hold e.mu.
+func (e *Exporter) appendLine(line []byte) error {
+	var request struct {
+		ResourceLogs []json.RawMessage `json:"resourceLogs"`
+	}
+	if err := json.Unmarshal(line, &request); err != nil {
+		return fmt.Errorf("otlp: invalid OTLP/JSON line: %w", err)
+	}
+
+	for _, rl := range request.ResourceLogs {
+		e.pending = append(e.pending, rl)
+		e.pendingBytes += len(rl)
+	}
+	e.trimPending()
+	return nil
+}
+
+// trimPending drops the oldest records beyond MaxPending. Callers must hold e.mu.
+func (e *Exporter) trimPending() {
+	for len(e.pending) > e.opts.MaxPending {
+		e.pendingBytes -= len(e.pending[0])
+		e.pending[0] = nil
+		e.pending = e.pending[1:]
+		e.dropped.Add(1)
+	}
+}
+
+func (e *Exporter) run() {
+	defer close(e.stopped)
+
+	ticker := time.NewTicker(e.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.report(e.export(e.ctx))
+		case <-e.kick:
+			e.report(e.export(e.ctx))
+		case req := <-e.flushReq:
+			req.reply <- e.export(req.ctx)
+		case <-e.done:
+			return
+		}
+	}
+}
+
+func (e *Exporter) report(err error) {
+	if err != nil && e.opts.OnError != nil {
+		e.opts.OnError(err)
+	}
+}
+
+// export sends the buffered records in batches until the buffer is empty. When
+// ctx is done, the current batch is put back and export stops.
+func (e *Exporter) export(ctx context.Context) error {
+	var errs []error
+	for {
+		batch := e.nextBatch()
+		if len(batch) == 0 {
+			return errors.Join(errs...)
+		}
+		if err := e.send(ctx, batch); err != nil {
+			if ctx.Err() != nil {
+				e.requeue(batch)
+				return errors.Join(append(errs, ctx.Err())...)
+			}
+			e.dropped.Add(uint64(len(batch)))
+			errs = append(errs, err)
+		}
+	}
+}
+
+// requeue puts a batch that could not be sent back at the front of the buffer,
+// still bounded by MaxPending: records written meanwhile may push the oldest out.
+func (e *Exporter) requeue(batch []json.RawMessage) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.pending = append(batch, e.pending...)
+	for _, rl := range batch {
+		e.pendingBytes += len(rl)
+	}
+	e.trimPending()
+}
+
+// nextBatch removes up to MaxBatchSize records (and MaxBatchBytes bytes) from the buffer.
+func (e *Exporter) nextBatch() []json.RawMessage {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	n, size := 0, 0
+	for n < len(e.pending) && n < e.opts.MaxBatchSize {
+		if n > 0 && size+len(e.pending[n]) > e.opts.MaxBatchBytes {
+			break
+		}
+		size += len(e.pending[n])
+		n++
+	}
+
+	batch := make([]json.RawMessage, n)
+	copy(batch, e.pending[:n])
+	clear(e.pending[:n])
+	e.pending = e.pending[n:]
+	e.pendingBytes -= size
+	return batch
+}
+
+// send posts one batch, retrying retryable failures until ctx is done.
+func (e *Exporter) send(ctx context.Context, batch []json.RawMessage) error {
+	body, err := e.encode(batch)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := e.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		var perm *permanentError
+		if errors.As(err, &perm) || attempt >= e.opts.MaxRetries {
+			return err
+		}
+
+		delay := e.backoff(attempt)
+		if retryAfter >= 0 {
+			// a collector asking for an hour must not stall Flush and Close
+			delay = min(retryAfter, e.opts.MaxBackoff)
+		}
+		if err := e.sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// encode builds the ExportLogsServiceRequest payload, gzip-compressed unless disabled.
+func (e *Exporter) encode(batch []json.RawMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gz *gzip.Writer
+	if !e.opts.DisableCompression {
+		gz = gzip.NewWriter(&buf)
+		w = gz
+	}
+
+	io.WriteString(w, `{"resourceLogs":[`)
+	for i, rl := range batch {
+		if i > 0 {
+			io.WriteString(w, ",")
+		}
+		w.Write(rl)
+	}
+	io.WriteString(w, `]}`)
+
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// permanentError marks a response that must not be retried.
+type permanentError struct {
+	status int
+	body   string
+}
+
+func (e *permanentError) Error() string {
+	return fmt.Sprintf("otlp: collector rejected batch with status %d: %s", e.status, e.body)
+}
+
+// post performs one request. It returns the Retry-After delay requested by the
+// collector, or -1 when none was given.
+func (e *Exporter) post(ctx context.Context, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.opts.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return -1, &permanentError{body: err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if !e.opts.DisableCompression {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for k, v := range e.opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.opts.Client.Do(req)
+	if err != nil {
+		return -1, fmt.Errorf("otlp: export failed: %w", err)
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return -1, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return parseRetryAfter(resp.Header.Get("Retry-After")),
+			fmt.Errorf("otlp: collector returned status %d: %s", resp.StatusCode, msg)
+	default:
+		return -1, &permanentError{status: resp.StatusCode, body: string(msg)}
+	}
+}
+
+// backoff returns the exponential delay before retry number attempt+1.
+func (e *Exporter) backoff(attempt int) time.Duration {
+	delay := e.opts.InitialBackoff
+	for i := 0; i < attempt && delay < e.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, e.opts.MaxBackoff)
+}
+
+// parseRetryAfter accepts both forms of the header: delay in seconds or HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return -1
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return -1
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,266 @@
+package otlp
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Astronotify/chronolog"
+)
+
+// collector is an httptest stand-in for an OpenTelemetry collector.
+type collector struct {
+	mu       sync.Mutex
+	requests []int // records per accepted request
+	failures int   // number of 503 responses still to return
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failures > 0 {
+		c.failures--
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	if r.Header.Get("Content-Encoding") != "gzip" || r.Header.Get("Content-Type") != "application/json" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	gz, err := gzip.NewReader(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var req struct {
+		ResourceLogs []struct {
+			ScopeLogs []struct {
+				LogRecords []json.RawMessage
+			}
+		}
+	}
+	if err := json.NewDecoder(gz).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	records := 0
+	for _, rl := range req.ResourceLogs {
+		for _, sl := range rl.ScopeLogs {
+			records += len(sl.LogRecords)
+		}
+	}
+	c.requests = append(c.requests, records)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *collector) received() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]int(nil), c.requests...)
+}
+
+func newTestExporter(t *testing.T, c *collector, opts Options) (*Exporter, *[]time.Duration) {
+	t.Helper()
+	srv := httptest.NewServer(c)
+	t.Cleanup(srv.Close)
+
+	opts.Endpoint = srv.URL + "/v1/logs"
+	exp, err := New(opts)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var delays []time.Duration
+	exp.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return exp, &delays
+}
+
+func TestBatchesByCount(t *testing.T) {
+	c := &collector{}
+	exp, _ := newTestExporter(t, c, Options{MaxBatchSize: 2, FlushInterval: time.Hour})
+
+	l := chronolog.New(chronolog.Config{Writer: exp, Format: chronolog.FormatOTLPJSON})
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		l.Info(ctx, "exported")
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	total := 0
+	for _, n := range c.received() {
+		if n > 2 {
+			t.Errorf("batch of %d records exceeds MaxBatchSize", n)
+		}
+		total += n
+	}
+	if total != 5 {
+		t.Errorf("expected 5 records exported, got %d (%v)", total, c.received())
+	}
+}
+
+func TestRetriesHonorRetryAfter(t *testing.T) {
+	c := &collector{failures: 2}
+	exp, delays := newTestExporter(t, c, Options{FlushInterval: time.Hour})
+
+	l := chronolog.New(chronolog.Config{Writer: exp, Format: chronolog.FormatOTLPJSON})
+	l.Warn(context.Background(), "retried")
+	if err := exp.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	exp.Close()
+
+	if got := c.received(); len(got) != 1 || got[0] != 1 {
+		t.Errorf("expected one successful request, got %v", got)
+	}
+	if len(*delays) != 2 || (*delays)[0] != 2*time.Second {
+		t.Errorf("expected two retries honoring Retry-After, got %v", *delays)
+	}
+}
+
+func TestPermanentFailureIsNotRetried(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	exp, err := New(Options{Endpoint: srv.URL, FlushInterval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp.sleep = func(context.Context, time.Duration) error {
+		t.Error("unexpected retry")
+		return nil
+	}
+
+	l := chronolog.New(chronolog.Config{Writer: exp, Format: chronolog.FormatOTLPJSON})
+	l.Info(context.Background(), "rejected")
+	if err := exp.Flush(context.Background()); err == nil {
+		t.Errorf("expected an error for a 400 response")
+	}
+	if exp.Dropped() != 1 {
+		t.Errorf("expected the batch to be dropped, got %d", exp.Dropped())
+	}
+	exp.Close()
+}
+
+func TestBackoffIsExponentialAndCapped(t *testing.T) {
+	e := &Exporter{opts: Options{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for attempt, w := range want {
+		if got := e.backoff(attempt); got != w {
+			t.Errorf("attempt %d: expected %v, got %v", attempt, w, got)
+		}
+	}
+}
+
+func TestRetryAfterIsCappedByMaxBackoff(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	exp, err := New(Options{Endpoint: srv.URL, FlushInterval: time.Hour, MaxRetries: 2, MaxBackoff: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var delays []time.Duration
+	exp.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	exp.Write([]byte(`{"resourceLogs":[{}]}` + "\n"))
+	exp.Flush(context.Background())
+	exp.Close()
+
+	if len(delays) != 2 || delays[0] != time.Second || delays[1] != time.Second {
+		t.Errorf("expected Retry-After capped at MaxBackoff, got %v", delays)
+	}
+}
+
+func TestFlushContextStopsRetries(t *testing.T) {
+	c := &collector{failures: 1000}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	exp, err := New(Options{Endpoint: srv.URL, FlushInterval: time.Hour, MaxRetries: 1000, MaxBackoff: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp.Write([]byte(`{"resourceLogs":[{}]}` + "\n"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := exp.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Flush() = %v, want the deadline error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Flush() returned after %v", elapsed)
+	}
+	if exp.Dropped() != 0 {
+		t.Errorf("records were dropped by a canceled flush: %d", exp.Dropped())
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelShutdown()
+	if err := exp.Shutdown(shutdownCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v, want the deadline error", err)
+	}
+	if exp.Dropped() != 1 {
+		t.Errorf("expected the unsent record to be dropped on shutdown, got %d", exp.Dropped())
+	}
+}
+
+func TestRequeueHonorsMaxPending(t *testing.T) {
+	c := &collector{failures: 1000}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	exp, err := New(Options{Endpoint: srv.URL, FlushInterval: time.Hour, MaxPending: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := []byte(`{"resourceLogs":[{}]}` + "\n")
+	exp.Write(line)
+	exp.Write(line)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var once sync.Once
+	exp.sleep = func(context.Context, time.Duration) error {
+		// records keep arriving while the batch in flight is being retried
+		once.Do(func() {
+			exp.Write(line)
+			exp.Write(line)
+			cancel()
+		})
+		return context.Canceled
+	}
+	if err := exp.export(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("export() = %v, want the cancellation", err)
+	}
+
+	exp.mu.Lock()
+	pending := len(exp.pending)
+	exp.mu.Unlock()
+	if pending != 2 || exp.Dropped() != 2 {
+		t.Errorf("pending = %d, dropped = %d, want 2 and 2", pending, exp.Dropped())
+	}
+	exp.Shutdown(ctx)
+}
@@ -0,0 +1,124 @@
+package chronolog
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"github.com/Astronotify/chronolog/entries"
+	"github.com/Astronotify/chronolog/internal"
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+// Span is a unit of work started with StartSpan. Its begin entry is logged when it
+// starts and its end entry, with the duration, status and error, when End is called.
+//
+// A Span is safe for concurrent use.
+type Span struct {
+	logger *Logger
+	ctx    context.Context
+	begin  entries.TraceBeginLogEntry
+
+	mu    sync.Mutex
+	attrs map[string]any
+	ended bool
+}
+
+// StartSpan starts a new span named name on the default logger. See Logger.StartSpan.
+//
+// Parameters:
+//   - ctx (context.Context): the context of the enclosing span, if any.
+//   - name (string): a label identifying the span (e.g., "LoadUserProfile").
+//   - additionalData (...map[string]any): optional structured metadata for the begin entry.
+//
+// Returns:
+//   - context.Context: the context carrying the new span, to pass to the work it covers.
+//   - *Span: the span, to end with Span.End.
+func StartSpan(ctx context.Context, name string, additionalData ...map[string]any) (context.Context, *Span) {
+	return Default().StartSpan(ctx, name, additionalData...)
+}
+
+// StartSpan starts a new span named name and logs its TraceBeginLogEntry.
+//
+// The span gets a random span ID and the current span of ctx, if any, becomes its
+// parent. A new random trace ID is generated when ctx carries none. Entries logged
+// with the returned context are correlated with the span.
+//
+// Parameters:
+//   - ctx (context.Context): the context of the enclosing span, if any.
+//   - name (string): a label identifying the span (e.g., "LoadUserProfile").
+//   - additionalData (...map[string]any): optional structured metadata for the begin entry.
+//
+// Returns:
+//   - context.Context: the context carrying the new span, to pass to the work it covers.
+//   - *Span: the span, to end with Span.End.
+func (l *Logger) StartSpan(ctx context.Context, name string, additionalData ...map[string]any) (context.Context, *Span) {
+	if internal.ExtractTraceID(ctx) == "" {
+		ctx = internal.WithTraceID(ctx, internal.NewTraceID())
+	}
+	// always overwritten, so that a stale parent of the enclosing span is not inherited
+	ctx = internal.WithParentSpanID(ctx, internal.ExtractSpanID(ctx))
+	ctx = internal.WithSpanID(ctx, internal.NewSpanID())
+
+	begin := entries.NewTraceBeginLogEntry(ctx, name, internal.MergeAdditionalData(additionalData...))
+	begin.LoggerName = l.name
+	l.write(ctx, begin)
+
+	return ctx, &Span{logger: l, ctx: ctx, begin: begin}
+}
+
+// TraceID returns the trace ID of the span.
+func (s *Span) TraceID() string {
+	return s.begin.TraceID
+}
+
+// SpanID returns the span ID of the span.
+func (s *Span) SpanID() string {
+	return s.begin.SpanID
+}
+
+// SetAttr records an attribute reported in the AdditionalData of the span's end
+// entry. Setting a key again replaces its value; calls after End are ignored.
+func (s *Span) SetAttr(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return
+	}
+	if s.attrs == nil {
+		s.attrs = make(map[string]any)
+	}
+	s.attrs[key] = value
+}
+
+// End logs the TraceEndLogEntry of the span with its duration and the attributes
+// set with SetAttr. When err is not nil, the entry is raised to Level.Error with
+// status entries.SpanStatusError and the error message, so that failed spans pass
+// the usual minimum levels; otherwise it keeps Level.Trace with entries.SpanStatusOK.
+//
+// Only the first call logs; later calls are ignored, so End can be deferred and
+// also called explicitly.
+//
+// Parameters:
+//   - err (error): the error that ended the span, or nil on success.
+func (s *Span) End(err error) {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	attrs := maps.Clone(s.attrs)
+	s.mu.Unlock()
+
+	entry := entries.NewTraceEndLogEntryFromBegin(s.begin, attrs)
+	entry.LoggerName = s.logger.name
+	entry.Status = entries.SpanStatusOK
+	if err != nil {
+		entry.Level = Level.Error
+		entry.Status = entries.SpanStatusError
+		entry.Error = err.Error()
+	}
+	s.logger.write(s.ctx, entry)
+}
@@ -0,0 +1,91 @@
+package chronolog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Astronotify/chronolog/internal"
+	Level "github.com/Astronotify/chronolog/level"
+)
+
+func TestSpanLifecycle(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Config{Writer: &buf, MinimumLogLevel: Level.Trace})
+
+	ctx := internal.WithSpanID(internal.WithTraceID(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736"), "00f067aa0ba902b7")
+	spanCtx, span := l.StartSpan(ctx, "LoadUserProfile")
+	span.SetAttr("user_id", "u-42")
+	span.End(errors.New("profile not found"))
+	span.End(nil)
+
+	if got := internal.ExtractParentSpanID(spanCtx); got != "00f067aa0ba902b7" {
+		t.Errorf("parent span ID = %q", got)
+	}
+	if id := span.SpanID(); len(id) != 16 || id == "00f067aa0ba902b7" || internal.ExtractSpanID(spanCtx) != id {
+		t.Errorf("span ID = %q", id)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected a begin and an end entry, got: %s", buf.String())
+	}
+	var begin, end map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &begin); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &end); err != nil {
+		t.Fatal(err)
+	}
+	if begin["event_type"] != "TraceBeginLogEntry" || end["event_type"] != "TraceEndLogEntry" {
+		t.Fatalf("unexpected entries: %s", buf.String())
+	}
+	for _, entry := range []map[string]any{begin, end} {
+		if entry["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" || entry["span_id"] != span.SpanID() || entry["parent_span_id"] != "00f067aa0ba902b7" {
+			t.Errorf("trace fields = %v, %v, %v", entry["trace_id"], entry["span_id"], entry["parent_span_id"])
+		}
+	}
+	if end["status"] != "error" || end["error"] != "profile not found" || end["level"] != "error" {
+		t.Errorf("status = %v, error = %v, level = %v", end["status"], end["error"], end["level"])
+	}
+	if data, _ := end["additional_data"].(map[string]any); data["user_id"] != "u-42" {
+		t.Errorf("attributes = %v", end["additional_data"])
+	}
+}
+
+func TestStartSpanNewTrace(t *testing.T) {
+	l := New(Config{Writer: &bytes.Buffer{}})
+
+	ctx, root := l.StartSpan(context.Background(), "root")
+	childCtx, child := l.StartSpan(ctx, "child")
+	root.End(nil)
+	child.End(nil)
+
+	if id := root.TraceID(); len(id) != 32 || child.TraceID() != id {
+		t.Errorf("trace IDs = %q, %q", id, child.TraceID())
+	}
+	if got := internal.ExtractParentSpanID(ctx); got != "" {
+		t.Errorf("root parent span ID = %q", got)
+	}
+	if got := internal.ExtractParentSpanID(childCtx); got != root.SpanID() {
+		t.Errorf("child parent span ID = %q, want %q", got, root.SpanID())
+	}
+}
+
+func TestFailedSpanPassesDefaultLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Config{Writer: &buf})
+
+	_, ok := l.StartSpan(context.Background(), "ok")
+	ok.End(nil)
+	_, failed := l.StartSpan(context.Background(), "failed")
+	failed.End(errors.New("timeout"))
+
+	out := strings.TrimSpace(buf.String())
+	if strings.Count(out, "\n") != 0 || !strings.Contains(out, `"name":"failed"`) || !strings.Contains(out, `"status":"error"`) {
+		t.Errorf("expected only the failed span end at info: %s", out)
+	}
+}
@@ -0,0 +1,48 @@
+package chronolog
+
+import (
+	"reflect"
+
+	"github.com/Astronotify/chronolog/entries"
+)
+
+var logEntryType = reflect.TypeOf(entries.LogEntry{})
+
+// updateLogEntry returns a copy of entry whose LogEntry, embedded or not, has been
+// modified by fn. The caller's value is never mutated, even when entry is a pointer.
+// Values that do not carry a LogEntry are returned unchanged.
+func updateLogEntry(entry any, fn func(*entries.LogEntry)) any {
+	v := reflect.ValueOf(entry)
+	if !v.IsValid() {
+		return entry
+	}
+
+	isPointer := v.Kind() == reflect.Pointer
+	if isPointer {
+		if v.IsNil() {
+			return entry
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return entry
+	}
+
+	clone := reflect.New(v.Type())
+	clone.Elem().Set(v)
+
+	var target reflect.Value
+	if v.Type() == logEntryType {
+		target = clone.Elem()
+	} else if field, ok := v.Type().FieldByName(logEntryType.Name()); ok && field.Anonymous && field.Type == logEntryType {
+		target = clone.Elem().FieldByIndex(field.Index)
+	} else {
+		return entry
+	}
+	fn(target.Addr().Interface().(*entries.LogEntry))
+
+	if isPointer {
+		return clone.Interface()
+	}
+	return clone.Elem().Interface()
+}